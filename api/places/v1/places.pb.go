@@ -0,0 +1,860 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: places/v1/places.proto
+
+package placesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchLocationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Язык ответа, например "ru" или "en-US"; пусто — язык провайдеров по умолчанию.
+	Lang          string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLocationsRequest) Reset() {
+	*x = SearchLocationsRequest{}
+	mi := &file_places_v1_places_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLocationsRequest) ProtoMessage() {}
+
+func (x *SearchLocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLocationsRequest.ProtoReflect.Descriptor instead.
+func (*SearchLocationsRequest) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchLocationsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchLocationsRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type SearchLocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*Location            `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLocationsResponse) Reset() {
+	*x = SearchLocationsResponse{}
+	mi := &file_places_v1_places_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLocationsResponse) ProtoMessage() {}
+
+func (x *SearchLocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLocationsResponse.ProtoReflect.Descriptor instead.
+func (*SearchLocationsResponse) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchLocationsResponse) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+type GetLocationDetailsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Язык ответа, например "ru" или "en-US"; пусто — язык провайдеров по умолчанию.
+	Lang string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	// Система единиц: metric (по умолчанию), imperial или standard.
+	Units         string `protobuf:"bytes,3,opt,name=units,proto3" json:"units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationDetailsRequest) Reset() {
+	*x = GetLocationDetailsRequest{}
+	mi := &file_places_v1_places_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationDetailsRequest) ProtoMessage() {}
+
+func (x *GetLocationDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetLocationDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLocationDetailsRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetLocationDetailsRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *GetLocationDetailsRequest) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Lat           float64                `protobuf:"fixed64,2,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,3,opt,name=lon,proto3" json:"lon,omitempty"`
+	Country       string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_places_v1_places_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Location) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Location) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type Weather struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Temp          float64                `protobuf:"fixed64,1,opt,name=temp,proto3" json:"temp,omitempty"`
+	FeelsLike     float64                `protobuf:"fixed64,2,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Humidity      int32                  `protobuf:"varint,4,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	WindSpeed     float64                `protobuf:"fixed64,5,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	Icon          string                 `protobuf:"bytes,6,opt,name=icon,proto3" json:"icon,omitempty"`
+	Units         string                 `protobuf:"bytes,7,opt,name=units,proto3" json:"units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Weather) Reset() {
+	*x = Weather{}
+	mi := &file_places_v1_places_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Weather) ProtoMessage() {}
+
+func (x *Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Weather.ProtoReflect.Descriptor instead.
+func (*Weather) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Weather) GetTemp() float64 {
+	if x != nil {
+		return x.Temp
+	}
+	return 0
+}
+
+func (x *Weather) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *Weather) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Weather) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *Weather) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *Weather) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Weather) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+type Place struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Xid         string                 `protobuf:"bytes,1,opt,name=xid,proto3" json:"xid,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Kinds       string                 `protobuf:"bytes,3,opt,name=kinds,proto3" json:"kinds,omitempty"`
+	Lat         float64                `protobuf:"fixed64,4,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon         float64                `protobuf:"fixed64,5,opt,name=lon,proto3" json:"lon,omitempty"`
+	Distance    float64                `protobuf:"fixed64,6,opt,name=distance,proto3" json:"distance,omitempty"`
+	Description string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	Image       string                 `protobuf:"bytes,8,opt,name=image,proto3" json:"image,omitempty"`
+	Website     string                 `protobuf:"bytes,9,opt,name=website,proto3" json:"website,omitempty"`
+	Wikipedia   string                 `protobuf:"bytes,10,opt,name=wikipedia,proto3" json:"wikipedia,omitempty"`
+	Units       string                 `protobuf:"bytes,11,opt,name=units,proto3" json:"units,omitempty"`
+	// Оценка полезности и коды причин; места в LocationResult упорядочены по оценке
+	Score        float64  `protobuf:"fixed64,12,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons      []string `protobuf:"bytes,13,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	OpeningHours string   `protobuf:"bytes,14,opt,name=opening_hours,json=openingHours,proto3" json:"opening_hours,omitempty"`
+	// Категории в таксономии сервиса; kinds сохраняет категории провайдера
+	Categories      []*CategoryRef `protobuf:"bytes,15,rep,name=categories,proto3" json:"categories,omitempty"`
+	PrimaryCategory *CategoryRef   `protobuf:"bytes,16,opt,name=primary_category,json=primaryCategory,proto3" json:"primary_category,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Place) Reset() {
+	*x = Place{}
+	mi := &file_places_v1_places_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Place) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Place) ProtoMessage() {}
+
+func (x *Place) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Place.ProtoReflect.Descriptor instead.
+func (*Place) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Place) GetXid() string {
+	if x != nil {
+		return x.Xid
+	}
+	return ""
+}
+
+func (x *Place) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Place) GetKinds() string {
+	if x != nil {
+		return x.Kinds
+	}
+	return ""
+}
+
+func (x *Place) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Place) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Place) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *Place) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Place) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Place) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+func (x *Place) GetWikipedia() string {
+	if x != nil {
+		return x.Wikipedia
+	}
+	return ""
+}
+
+func (x *Place) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *Place) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Place) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *Place) GetOpeningHours() string {
+	if x != nil {
+		return x.OpeningHours
+	}
+	return ""
+}
+
+func (x *Place) GetCategories() []*CategoryRef {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Place) GetPrimaryCategory() *CategoryRef {
+	if x != nil {
+		return x.PrimaryCategory
+	}
+	return nil
+}
+
+type CategoryRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Icon          string                 `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryRef) Reset() {
+	*x = CategoryRef{}
+	mi := &file_places_v1_places_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryRef) ProtoMessage() {}
+
+func (x *CategoryRef) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryRef.ProtoReflect.Descriptor instead.
+func (*CategoryRef) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CategoryRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CategoryRef) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *CategoryRef) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+type LocationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Weather       *Weather               `protobuf:"bytes,2,opt,name=weather,proto3" json:"weather,omitempty"`
+	Places        []*Place               `protobuf:"bytes,3,rep,name=places,proto3" json:"places,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationResult) Reset() {
+	*x = LocationResult{}
+	mi := &file_places_v1_places_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationResult) ProtoMessage() {}
+
+func (x *LocationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationResult.ProtoReflect.Descriptor instead.
+func (*LocationResult) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LocationResult) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *LocationResult) GetWeather() *Weather {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *LocationResult) GetPlaces() []*Place {
+	if x != nil {
+		return x.Places
+	}
+	return nil
+}
+
+// LocationEvent — частичный результат потоковой выдачи деталей.
+type LocationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*LocationEvent_Weather
+	//	*LocationEvent_Place
+	Event         isLocationEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationEvent) Reset() {
+	*x = LocationEvent{}
+	mi := &file_places_v1_places_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationEvent) ProtoMessage() {}
+
+func (x *LocationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_places_v1_places_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationEvent.ProtoReflect.Descriptor instead.
+func (*LocationEvent) Descriptor() ([]byte, []int) {
+	return file_places_v1_places_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LocationEvent) GetEvent() isLocationEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *LocationEvent) GetWeather() *Weather {
+	if x != nil {
+		if x, ok := x.Event.(*LocationEvent_Weather); ok {
+			return x.Weather
+		}
+	}
+	return nil
+}
+
+func (x *LocationEvent) GetPlace() *Place {
+	if x != nil {
+		if x, ok := x.Event.(*LocationEvent_Place); ok {
+			return x.Place
+		}
+	}
+	return nil
+}
+
+type isLocationEvent_Event interface {
+	isLocationEvent_Event()
+}
+
+type LocationEvent_Weather struct {
+	Weather *Weather `protobuf:"bytes,1,opt,name=weather,proto3,oneof"`
+}
+
+type LocationEvent_Place struct {
+	Place *Place `protobuf:"bytes,2,opt,name=place,proto3,oneof"`
+}
+
+func (*LocationEvent_Weather) isLocationEvent_Event() {}
+
+func (*LocationEvent_Place) isLocationEvent_Event() {}
+
+var File_places_v1_places_proto protoreflect.FileDescriptor
+
+const file_places_v1_places_proto_rawDesc = "" +
+	"\n" +
+	"\x16places/v1/places.proto\x12\tplaces.v1\"B\n" +
+	"\x16SearchLocationsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\"L\n" +
+	"\x17SearchLocationsResponse\x121\n" +
+	"\tlocations\x18\x01 \x03(\v2\x13.places.v1.LocationR\tlocations\"v\n" +
+	"\x19GetLocationDetailsRequest\x12/\n" +
+	"\blocation\x18\x01 \x01(\v2\x13.places.v1.LocationR\blocation\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\x12\x14\n" +
+	"\x05units\x18\x03 \x01(\tR\x05units\"r\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03lat\x18\x02 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x03 \x01(\x01R\x03lon\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x14\n" +
+	"\x05state\x18\x05 \x01(\tR\x05state\"\xc3\x01\n" +
+	"\aWeather\x12\x12\n" +
+	"\x04temp\x18\x01 \x01(\x01R\x04temp\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x02 \x01(\x01R\tfeelsLike\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bhumidity\x18\x04 \x01(\x05R\bhumidity\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x05 \x01(\x01R\twindSpeed\x12\x12\n" +
+	"\x04icon\x18\x06 \x01(\tR\x04icon\x12\x14\n" +
+	"\x05units\x18\a \x01(\tR\x05units\"\xd9\x03\n" +
+	"\x05Place\x12\x10\n" +
+	"\x03xid\x18\x01 \x01(\tR\x03xid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05kinds\x18\x03 \x01(\tR\x05kinds\x12\x10\n" +
+	"\x03lat\x18\x04 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x05 \x01(\x01R\x03lon\x12\x1a\n" +
+	"\bdistance\x18\x06 \x01(\x01R\bdistance\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x14\n" +
+	"\x05image\x18\b \x01(\tR\x05image\x12\x18\n" +
+	"\awebsite\x18\t \x01(\tR\awebsite\x12\x1c\n" +
+	"\twikipedia\x18\n" +
+	" \x01(\tR\twikipedia\x12\x14\n" +
+	"\x05units\x18\v \x01(\tR\x05units\x12\x14\n" +
+	"\x05score\x18\f \x01(\x01R\x05score\x12\x18\n" +
+	"\areasons\x18\r \x03(\tR\areasons\x12#\n" +
+	"\ropening_hours\x18\x0e \x01(\tR\fopeningHours\x126\n" +
+	"\n" +
+	"categories\x18\x0f \x03(\v2\x16.places.v1.CategoryRefR\n" +
+	"categories\x12A\n" +
+	"\x10primary_category\x18\x10 \x01(\v2\x16.places.v1.CategoryRefR\x0fprimaryCategory\"G\n" +
+	"\vCategoryRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x12\n" +
+	"\x04icon\x18\x03 \x01(\tR\x04icon\"\x99\x01\n" +
+	"\x0eLocationResult\x12/\n" +
+	"\blocation\x18\x01 \x01(\v2\x13.places.v1.LocationR\blocation\x12,\n" +
+	"\aweather\x18\x02 \x01(\v2\x12.places.v1.WeatherR\aweather\x12(\n" +
+	"\x06places\x18\x03 \x03(\v2\x10.places.v1.PlaceR\x06places\"r\n" +
+	"\rLocationEvent\x12.\n" +
+	"\aweather\x18\x01 \x01(\v2\x12.places.v1.WeatherH\x00R\aweather\x12(\n" +
+	"\x05place\x18\x02 \x01(\v2\x10.places.v1.PlaceH\x00R\x05placeB\a\n" +
+	"\x05event2\x9b\x02\n" +
+	"\rPlacesService\x12X\n" +
+	"\x0fSearchLocations\x12!.places.v1.SearchLocationsRequest\x1a\".places.v1.SearchLocationsResponse\x12U\n" +
+	"\x12GetLocationDetails\x12$.places.v1.GetLocationDetailsRequest\x1a\x19.places.v1.LocationResult\x12Y\n" +
+	"\x15StreamLocationDetails\x12$.places.v1.GetLocationDetailsRequest\x1a\x18.places.v1.LocationEvent0\x01B\x1fZ\x1dplaces/api/places/v1;placesv1b\x06proto3"
+
+var (
+	file_places_v1_places_proto_rawDescOnce sync.Once
+	file_places_v1_places_proto_rawDescData []byte
+)
+
+func file_places_v1_places_proto_rawDescGZIP() []byte {
+	file_places_v1_places_proto_rawDescOnce.Do(func() {
+		file_places_v1_places_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_places_v1_places_proto_rawDesc), len(file_places_v1_places_proto_rawDesc)))
+	})
+	return file_places_v1_places_proto_rawDescData
+}
+
+var file_places_v1_places_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_places_v1_places_proto_goTypes = []any{
+	(*SearchLocationsRequest)(nil),    // 0: places.v1.SearchLocationsRequest
+	(*SearchLocationsResponse)(nil),   // 1: places.v1.SearchLocationsResponse
+	(*GetLocationDetailsRequest)(nil), // 2: places.v1.GetLocationDetailsRequest
+	(*Location)(nil),                  // 3: places.v1.Location
+	(*Weather)(nil),                   // 4: places.v1.Weather
+	(*Place)(nil),                     // 5: places.v1.Place
+	(*CategoryRef)(nil),               // 6: places.v1.CategoryRef
+	(*LocationResult)(nil),            // 7: places.v1.LocationResult
+	(*LocationEvent)(nil),             // 8: places.v1.LocationEvent
+}
+var file_places_v1_places_proto_depIdxs = []int32{
+	3,  // 0: places.v1.SearchLocationsResponse.locations:type_name -> places.v1.Location
+	3,  // 1: places.v1.GetLocationDetailsRequest.location:type_name -> places.v1.Location
+	6,  // 2: places.v1.Place.categories:type_name -> places.v1.CategoryRef
+	6,  // 3: places.v1.Place.primary_category:type_name -> places.v1.CategoryRef
+	3,  // 4: places.v1.LocationResult.location:type_name -> places.v1.Location
+	4,  // 5: places.v1.LocationResult.weather:type_name -> places.v1.Weather
+	5,  // 6: places.v1.LocationResult.places:type_name -> places.v1.Place
+	4,  // 7: places.v1.LocationEvent.weather:type_name -> places.v1.Weather
+	5,  // 8: places.v1.LocationEvent.place:type_name -> places.v1.Place
+	0,  // 9: places.v1.PlacesService.SearchLocations:input_type -> places.v1.SearchLocationsRequest
+	2,  // 10: places.v1.PlacesService.GetLocationDetails:input_type -> places.v1.GetLocationDetailsRequest
+	2,  // 11: places.v1.PlacesService.StreamLocationDetails:input_type -> places.v1.GetLocationDetailsRequest
+	1,  // 12: places.v1.PlacesService.SearchLocations:output_type -> places.v1.SearchLocationsResponse
+	7,  // 13: places.v1.PlacesService.GetLocationDetails:output_type -> places.v1.LocationResult
+	8,  // 14: places.v1.PlacesService.StreamLocationDetails:output_type -> places.v1.LocationEvent
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_places_v1_places_proto_init() }
+func file_places_v1_places_proto_init() {
+	if File_places_v1_places_proto != nil {
+		return
+	}
+	file_places_v1_places_proto_msgTypes[8].OneofWrappers = []any{
+		(*LocationEvent_Weather)(nil),
+		(*LocationEvent_Place)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_places_v1_places_proto_rawDesc), len(file_places_v1_places_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_places_v1_places_proto_goTypes,
+		DependencyIndexes: file_places_v1_places_proto_depIdxs,
+		MessageInfos:      file_places_v1_places_proto_msgTypes,
+	}.Build()
+	File_places_v1_places_proto = out.File
+	file_places_v1_places_proto_goTypes = nil
+	file_places_v1_places_proto_depIdxs = nil
+}
@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: places/v1/places.proto
+
+package placesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PlacesService_SearchLocations_FullMethodName       = "/places.v1.PlacesService/SearchLocations"
+	PlacesService_GetLocationDetails_FullMethodName    = "/places.v1.PlacesService/GetLocationDetails"
+	PlacesService_StreamLocationDetails_FullMethodName = "/places.v1.PlacesService/StreamLocationDetails"
+)
+
+// PlacesServiceClient is the client API for PlacesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PlacesService повторяет HTTP API: поиск локаций и получение деталей по локации.
+type PlacesServiceClient interface {
+	// SearchLocations ищет локации по названию.
+	SearchLocations(ctx context.Context, in *SearchLocationsRequest, opts ...grpc.CallOption) (*SearchLocationsResponse, error)
+	// GetLocationDetails возвращает погоду и интересные места одним ответом.
+	GetLocationDetails(ctx context.Context, in *GetLocationDetailsRequest, opts ...grpc.CallOption) (*LocationResult, error)
+	// StreamLocationDetails отдаёт погоду и каждое место по мере готовности.
+	// Места приходят с оценкой score, но не упорядочены по ней.
+	StreamLocationDetails(ctx context.Context, in *GetLocationDetailsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LocationEvent], error)
+}
+
+type placesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlacesServiceClient(cc grpc.ClientConnInterface) PlacesServiceClient {
+	return &placesServiceClient{cc}
+}
+
+func (c *placesServiceClient) SearchLocations(ctx context.Context, in *SearchLocationsRequest, opts ...grpc.CallOption) (*SearchLocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchLocationsResponse)
+	err := c.cc.Invoke(ctx, PlacesService_SearchLocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *placesServiceClient) GetLocationDetails(ctx context.Context, in *GetLocationDetailsRequest, opts ...grpc.CallOption) (*LocationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LocationResult)
+	err := c.cc.Invoke(ctx, PlacesService_GetLocationDetails_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *placesServiceClient) StreamLocationDetails(ctx context.Context, in *GetLocationDetailsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LocationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PlacesService_ServiceDesc.Streams[0], PlacesService_StreamLocationDetails_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetLocationDetailsRequest, LocationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlacesService_StreamLocationDetailsClient = grpc.ServerStreamingClient[LocationEvent]
+
+// PlacesServiceServer is the server API for PlacesService service.
+// All implementations must embed UnimplementedPlacesServiceServer
+// for forward compatibility.
+//
+// PlacesService повторяет HTTP API: поиск локаций и получение деталей по локации.
+type PlacesServiceServer interface {
+	// SearchLocations ищет локации по названию.
+	SearchLocations(context.Context, *SearchLocationsRequest) (*SearchLocationsResponse, error)
+	// GetLocationDetails возвращает погоду и интересные места одним ответом.
+	GetLocationDetails(context.Context, *GetLocationDetailsRequest) (*LocationResult, error)
+	// StreamLocationDetails отдаёт погоду и каждое место по мере готовности.
+	// Места приходят с оценкой score, но не упорядочены по ней.
+	StreamLocationDetails(*GetLocationDetailsRequest, grpc.ServerStreamingServer[LocationEvent]) error
+	mustEmbedUnimplementedPlacesServiceServer()
+}
+
+// UnimplementedPlacesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPlacesServiceServer struct{}
+
+func (UnimplementedPlacesServiceServer) SearchLocations(context.Context, *SearchLocationsRequest) (*SearchLocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchLocations not implemented")
+}
+func (UnimplementedPlacesServiceServer) GetLocationDetails(context.Context, *GetLocationDetailsRequest) (*LocationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocationDetails not implemented")
+}
+func (UnimplementedPlacesServiceServer) StreamLocationDetails(*GetLocationDetailsRequest, grpc.ServerStreamingServer[LocationEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLocationDetails not implemented")
+}
+func (UnimplementedPlacesServiceServer) mustEmbedUnimplementedPlacesServiceServer() {}
+func (UnimplementedPlacesServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafePlacesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlacesServiceServer will
+// result in compilation errors.
+type UnsafePlacesServiceServer interface {
+	mustEmbedUnimplementedPlacesServiceServer()
+}
+
+func RegisterPlacesServiceServer(s grpc.ServiceRegistrar, srv PlacesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPlacesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PlacesService_ServiceDesc, srv)
+}
+
+func _PlacesService_SearchLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlacesServiceServer).SearchLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlacesService_SearchLocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlacesServiceServer).SearchLocations(ctx, req.(*SearchLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlacesService_GetLocationDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLocationDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlacesServiceServer).GetLocationDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlacesService_GetLocationDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlacesServiceServer).GetLocationDetails(ctx, req.(*GetLocationDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlacesService_StreamLocationDetails_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLocationDetailsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlacesServiceServer).StreamLocationDetails(m, &grpc.GenericServerStream[GetLocationDetailsRequest, LocationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlacesService_StreamLocationDetailsServer = grpc.ServerStreamingServer[LocationEvent]
+
+// PlacesService_ServiceDesc is the grpc.ServiceDesc for PlacesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlacesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "places.v1.PlacesService",
+	HandlerType: (*PlacesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchLocations",
+			Handler:    _PlacesService_SearchLocations_Handler,
+		},
+		{
+			MethodName: "GetLocationDetails",
+			Handler:    _PlacesService_GetLocationDetails_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLocationDetails",
+			Handler:       _PlacesService_StreamLocationDetails_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "places/v1/places.proto",
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"places/internal/mockserver"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "адрес сервера")
+	fixtures := flag.String("fixtures", "", "каталог с фикстурами; пусто — встроенный набор")
+	latency := flag.Duration("latency", 0, "задержка каждого ответа")
+	jitter := flag.Duration("jitter", 0, "случайная добавка к задержке")
+	errorRate := flag.Float64("error-rate", 0, "доля ответов 500, от 0 до 1")
+	throttleRate := flag.Float64("throttle-rate", 0, "доля ответов 429, от 0 до 1")
+	retryAfter := flag.Duration("retry-after", time.Second, "Retry-After для ответов 429")
+	flag.Parse()
+
+	cfg := mockserver.Config{
+		Latency:      *latency,
+		Jitter:       *jitter,
+		ErrorRate:    *errorRate,
+		ThrottleRate: *throttleRate,
+		RetryAfter:   *retryAfter,
+	}
+	if *fixtures != "" {
+		cfg.Fixtures = os.DirFS(*fixtures)
+	}
+
+	log.Printf("places-mock listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mockserver.New(cfg)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"places/internal/app"
+	"places/internal/util"
 	"runtime"
 )
 
@@ -11,6 +12,13 @@ func main() {
 
 	application := app.NewApp()
 
+	// Переменные окружения читаются после NewApp: он загружает config.env
+	go func() {
+		if err := application.RunGRPC(util.GetEnv("GRPC_ADDR", ":9090")); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	if err := application.Run(":8080"); err != nil {
 		log.Fatal(err)
 	}
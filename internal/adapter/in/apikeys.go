@@ -0,0 +1,188 @@
+package in
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// apiKeyHeader передаёт ключ API; также принимается Authorization: Bearer <key>
+const apiKeyHeader = "X-API-Key"
+
+type APIKeyHandler struct {
+	src service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		src: service,
+	}
+}
+
+type createKeyRequest struct {
+	Name       string `json:"name"`
+	DailyQuota int    `json:"daily_quota"`
+}
+
+type createKeyResponse struct {
+	Key    string       `json:"key"`
+	APIKey model.APIKey `json:"api_key"`
+}
+
+type updateKeyRequest struct {
+	DailyQuota *int  `json:"daily_quota"`
+	Disabled   *bool `json:"disabled"`
+}
+
+// RequireAPIKey пропускает только запросы с действующим ключом и неисчерпанной квотой.
+// Идентификатор ключа кладётся в контекст для учёта обращений к провайдерам
+func (h *APIKeyHandler) RequireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r, ok := h.authenticate(w, r); ok {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// OptionalAPIKey проверяет ключ, только если клиент его передал: анонимные запросы
+// проходят, а запрос с недействительным ключом или исчерпанной квотой отклоняется.
+// Так и при REQUIRE_API_KEY=false пользователь остаётся привязан к ключу
+func (h *APIKeyHandler) OptionalAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKeyFromRequest(r) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r, ok := h.authenticate(w, r); ok {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// authenticate проверяет ключ запроса и при ошибке сам отвечает клиенту
+func (h *APIKeyHandler) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	key, err := h.src.Authenticate(r.Context(), apiKeyFromRequest(r))
+	switch {
+	case errors.Is(err, model.ErrQuotaExceeded):
+		w.Header().Set("Retry-After", strconv.Itoa(secondsUntilNextDay()))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return nil, false
+	case errors.Is(err, model.ErrUnauthorized):
+		unauthorized(w, "Valid API key is required")
+		return nil, false
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return r.WithContext(service.WithAPIKeyID(r.Context(), key.ID)), true
+}
+
+// RequireAdmin защищает управление ключами отдельным административным токеном
+func RequireAdmin(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := apiKeyFromRequest(r)
+			if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "Admin token is required", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req createKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if req.DailyQuota < 0 {
+		http.Error(w, "Daily quota must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	key, token, err := h.src.CreateKey(r.Context(), req.Name, req.DailyQuota)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, createKeyResponse{Key: token, APIKey: *key})
+}
+
+func (h *APIKeyHandler) Keys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.src.Keys(r.Context())
+	writeResult(w, keys, err)
+}
+
+func (h *APIKeyHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
+	var req updateKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.DailyQuota != nil && *req.DailyQuota < 0 {
+		http.Error(w, "Daily quota must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.src.UpdateKey(r.Context(), mux.Vars(r)["id"], req.DailyQuota, req.Disabled)
+	writeResult(w, key, err)
+}
+
+func (h *APIKeyHandler) DeleteKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.src.DeleteKey(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Usage отдаёт использование ключей за последние days дней (по умолчанию 30)
+func (h *APIKeyHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	days, err := intParam(r, "days", 30)
+	if err != nil || days < 1 {
+		http.Error(w, "days must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -(days - 1))
+
+	reports, err := h.src.Usage(r.Context(), from, to)
+	writeResult(w, reports, err)
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="places"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+func secondsUntilNextDay() int {
+	now := time.Now().UTC()
+	next := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	return int(next.Sub(now).Seconds()) + 1
+}
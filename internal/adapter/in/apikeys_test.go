@@ -0,0 +1,177 @@
+package in
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// authFunc принимает ключи из карты; ключ "exhausted" исчерпал квоту
+type authFunc struct {
+	service.APIKeyService
+	keys map[string]model.APIKey
+}
+
+func (a authFunc) Authenticate(_ context.Context, token string) (*model.APIKey, error) {
+	if token == "exhausted" {
+		return nil, fmt.Errorf("api key: %w", model.ErrQuotaExceeded)
+	}
+	key, ok := a.keys[token]
+	if !ok {
+		return nil, model.ErrUnauthorized
+	}
+	return &key, nil
+}
+
+// echoUser отвечает ключом и пользователем из контекста
+var echoUser = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(service.APIKeyIDFromContext(r.Context()) + "|" + service.UserIDFromContext(r.Context())))
+})
+
+func TestRequireAPIKey(t *testing.T) {
+	h := NewAPIKeyHandler(authFunc{keys: map[string]model.APIKey{"pk_alpha": {ID: "k1"}, "pk_beta": {ID: "k2"}}})
+	handler := h.RequireAPIKey(UserContext(echoUser))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{"no key", nil, http.StatusUnauthorized, ""},
+		{"unknown key", map[string]string{"X-API-Key": "pk_gamma"}, http.StatusUnauthorized, ""},
+		{"X-API-Key", map[string]string{"X-API-Key": "pk_alpha"}, http.StatusOK, "k1|k1"},
+		{"bearer", map[string]string{"Authorization": "Bearer pk_beta"}, http.StatusOK, "k2|k2"},
+		{"basic is not a key", map[string]string{"Authorization": "Basic pk_beta"}, http.StatusUnauthorized, ""},
+		{"user scoped by key", map[string]string{"X-API-Key": "pk_alpha", "X-User-ID": "alice"}, http.StatusOK, "k1|k1/alice"},
+		{"same user, other key", map[string]string{"X-API-Key": "pk_beta", "X-User-ID": "alice"}, http.StatusOK, "k2|k2/alice"},
+		{"quota exceeded", map[string]string{"X-API-Key": "exhausted"}, http.StatusTooManyRequests, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body, tt.body)
+			}
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate")
+			}
+			if tt.status == http.StatusTooManyRequests {
+				if seconds, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || seconds < 1 || seconds > 86401 {
+					t.Errorf("Retry-After = %q", rec.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
+
+func TestOptionalAPIKey(t *testing.T) {
+	h := NewAPIKeyHandler(authFunc{keys: map[string]model.APIKey{"pk_alpha": {ID: "k1"}}})
+	handler := h.OptionalAPIKey(UserContext(echoUser))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		// Без ключа X-User-ID ничем не подтверждён: запрос анонимный
+		{"anonymous", map[string]string{"X-User-ID": "alice"}, http.StatusOK, "|"},
+		{"with key", map[string]string{"X-API-Key": "pk_alpha", "X-User-ID": "alice"}, http.StatusOK, "k1|k1/alice"},
+		{"invalid key is not ignored", map[string]string{"X-API-Key": "pk_gamma"}, http.StatusUnauthorized, ""},
+		{"quota applies", map[string]string{"X-API-Key": "exhausted"}, http.StatusTooManyRequests, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		headers map[string]string
+		status  int
+	}{
+		{"correct token", "secret", map[string]string{"X-API-Key": "secret"}, http.StatusOK},
+		{"bearer", "secret", map[string]string{"Authorization": "Bearer secret"}, http.StatusOK},
+		{"wrong token", "secret", map[string]string{"X-API-Key": "secret2"}, http.StatusUnauthorized},
+		{"prefix of the token", "secret", map[string]string{"X-API-Key": "sec"}, http.StatusUnauthorized},
+		{"no token", "secret", nil, http.StatusUnauthorized},
+		// Без настроенного токена администрирование закрыто, даже с пустым заголовком
+		{"admin disabled", "", nil, http.StatusUnauthorized},
+		{"admin disabled, any token", "", map[string]string{"X-API-Key": "anything"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/keys", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			RequireAdmin(tt.token)(echoUser).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+// listsFunc отдаёт имя пользователя, для которого запрошены списки
+type listsFunc struct {
+	service.FavoritesService
+}
+
+func (listsFunc) Lists(_ context.Context, userID string) ([]model.FavoriteList, error) {
+	return []model.FavoriteList{{Name: userID}}, nil
+}
+
+func TestFavoritesRequireCredential(t *testing.T) {
+	keys := NewAPIKeyHandler(authFunc{keys: map[string]model.APIKey{"pk_alpha": {ID: "k1"}}})
+	handler := keys.OptionalAPIKey(UserContext(http.HandlerFunc(NewFavoritesHandler(listsFunc{}).Lists)))
+
+	// X-User-ID без ключа не даёт доступа к чужим данным
+	req := httptest.NewRequest(http.MethodGet, "/api/favorites", nil)
+	req.Header.Set("X-User-ID", "alice")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status %d, want 401", rec.Code)
+	}
+
+	req.Header.Set("X-API-Key", "pk_alpha")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"k1/alice"`) {
+		t.Errorf("with key: status %d, body %s", rec.Code, rec.Body)
+	}
+}
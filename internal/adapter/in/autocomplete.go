@@ -0,0 +1,94 @@
+package in
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"places/internal/service"
+)
+
+// errSuperseded — причина отмены подсказки, которую вытеснил более новый запрос того же поля ввода
+var errSuperseded = errors.New("superseded by a newer request")
+
+// autocompleteMaxAge — подсказки меняются не чаще геокодинга
+const autocompleteMaxAge = searchMaxAge
+
+type AutocompleteHandler struct {
+	src      service.Service
+	clientIP func(r *http.Request) string
+
+	mu sync.Mutex
+	// inflight — отмена выполняющегося запроса по сессии поля ввода
+	inflight map[string]*inflightRequest
+}
+
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// NewAutocompleteHandler создает обработчик подсказок. clientIP определяет адрес клиента
+// с учётом доверенных прокси: сессии ввода разных клиентов не должны пересекаться
+func NewAutocompleteHandler(service service.Service, clientIP func(r *http.Request) string) *AutocompleteHandler {
+	return &AutocompleteHandler{
+		src:      service,
+		clientIP: clientIP,
+		inflight: make(map[string]*inflightRequest),
+	}
+}
+
+// Autocomplete — подсказки локаций по мере ввода: GET /api/autocomplete?q=&session=
+// Клиент передаёт случайный session, постоянный для поля ввода; новый запрос с той же сессией
+// отменяет предыдущий, если тот ещё ждёт провайдера
+func (h *AutocompleteHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Query is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, done := h.begin(r)
+	defer done()
+
+	locations, err := h.src.Autocomplete(ctx, query)
+	if errors.Is(context.Cause(ctx), errSuperseded) {
+		http.Error(w, "Superseded by a newer request", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeCacheableJSON(w, r, locations, autocompleteMaxAge)
+}
+
+// begin регистрирует запрос сессии и отменяет предыдущий. Сессия действует в пределах
+// ключа API и адреса клиента: чужой клиент с тем же session не отменит запрос
+func (h *AutocompleteHandler) begin(r *http.Request) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(r.Context())
+	session := r.URL.Query().Get("session")
+	if session == "" {
+		return ctx, func() { cancel(nil) }
+	}
+
+	key := service.APIKeyIDFromContext(r.Context()) + "\x00" + h.clientIP(r) + "\x00" + session
+	current := &inflightRequest{cancel: cancel}
+
+	h.mu.Lock()
+	if previous, ok := h.inflight[key]; ok {
+		previous.cancel(errSuperseded)
+	}
+	h.inflight[key] = current
+	h.mu.Unlock()
+
+	return ctx, func() {
+		h.mu.Lock()
+		if h.inflight[key] == current {
+			delete(h.inflight, key)
+		}
+		h.mu.Unlock()
+		cancel(nil)
+	}
+}
@@ -0,0 +1,114 @@
+package in
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// blockingAutocomplete отвечает на запрос "wait" только после отмены или закрытия release
+type blockingAutocomplete struct {
+	service.Service
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingAutocomplete) Autocomplete(ctx context.Context, query string) ([]model.Location, error) {
+	if query != "wait" {
+		return []model.Location{{Name: query}}, nil
+	}
+	b.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.release:
+		return []model.Location{{Name: query}}, nil
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, _ := net.SplitHostPort(r.RemoteAddr)
+	return host
+}
+
+func autocompleteRequest(query, session, remoteAddr, keyID string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/api/autocomplete?q="+query+"&session="+session, nil)
+	r.RemoteAddr = remoteAddr
+	if keyID != "" {
+		r = r.WithContext(service.WithAPIKeyID(r.Context(), keyID))
+	}
+	return r
+}
+
+// startWaiting запускает запрос "wait" и дожидается, пока он дойдёт до сервиса
+func startWaiting(t *testing.T, h *AutocompleteHandler, src *blockingAutocomplete, r *http.Request) <-chan *httptest.ResponseRecorder {
+	t.Helper()
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.Autocomplete(rec, r)
+		done <- rec
+	}()
+	select {
+	case <-src.started:
+	case <-time.After(time.Second):
+		t.Fatal("request did not reach the service")
+	}
+	return done
+}
+
+func TestAutocompleteSuperseded(t *testing.T) {
+	src := &blockingAutocomplete{started: make(chan struct{}, 1), release: make(chan struct{})}
+	h := NewAutocompleteHandler(src, remoteIP)
+
+	first := startWaiting(t, h, src, autocompleteRequest("wait", "s1", "192.0.2.1:1000", "k1"))
+
+	// Новый запрос той же сессии того же клиента отменяет предыдущий
+	rec := httptest.NewRecorder()
+	h.Autocomplete(rec, autocompleteRequest("Новос", "s1", "192.0.2.1:1001", "k1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("newer request: status %d", rec.Code)
+	}
+	select {
+	case rec := <-first:
+		if rec.Code != http.StatusConflict {
+			t.Errorf("superseded request: status %d, want 409", rec.Code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("superseded request was not canceled")
+	}
+}
+
+func TestAutocompleteSessionsAreScoped(t *testing.T) {
+	src := &blockingAutocomplete{started: make(chan struct{}, 1), release: make(chan struct{})}
+	h := NewAutocompleteHandler(src, remoteIP)
+
+	first := startWaiting(t, h, src, autocompleteRequest("wait", "s1", "192.0.2.1:1000", "k1"))
+
+	// Та же сессия с другого адреса, с другим ключом или без сессии не отменяет запрос
+	for _, r := range []*http.Request{
+		autocompleteRequest("Новос", "s1", "192.0.2.2:1000", "k1"),
+		autocompleteRequest("Новос", "s1", "192.0.2.1:1000", "k2"),
+		autocompleteRequest("Новос", "", "192.0.2.1:1000", "k1"),
+	} {
+		rec := httptest.NewRecorder()
+		h.Autocomplete(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status %d", rec.Code)
+		}
+	}
+
+	close(src.release)
+	if rec := <-first; rec.Code != http.StatusOK {
+		t.Errorf("first request: status %d, want 200", rec.Code)
+	}
+	if len(h.inflight) != 0 {
+		t.Errorf("inflight = %v, want finished sessions removed", h.inflight)
+	}
+}
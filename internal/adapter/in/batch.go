@@ -0,0 +1,80 @@
+package in
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+type BatchHandler struct {
+	src         service.BatchService
+	limiter     *RateLimiter
+	costPerItem int
+	maxItems    int
+}
+
+// NewBatchHandler создает обработчик пакетных запросов. Каждая локация пакета
+// списывает costPerItem токенов из того же лимита, что и одиночные запросы деталей;
+// пакет дороже burst ограничителя отклоняется с 413, поэтому maxItems×costPerItem
+// не должно превышать burst
+func NewBatchHandler(service service.BatchService, limiter *RateLimiter, costPerItem, maxItems int) *BatchHandler {
+	return &BatchHandler{
+		src:         service,
+		limiter:     limiter,
+		costPerItem: costPerItem,
+		maxItems:    maxItems,
+	}
+}
+
+type batchDetailsRequest struct {
+	Locations []model.Location `json:"locations"`
+	Lang      string           `json:"lang,omitempty"`
+	Units     string           `json:"units,omitempty"`
+}
+
+type batchDetailsResponse struct {
+	Results []model.LocationResult `json:"results"`
+}
+
+// GetLocationDetailsBatch — детали нескольких локаций за один запрос:
+// POST /api/locations/details:batch
+func (h *BatchHandler) GetLocationDetailsBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchDetailsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Locations) == 0 {
+		http.Error(w, "Locations are required", http.StatusBadRequest)
+		return
+	}
+	// Размер проверяется до списания токенов, чтобы заведомо отклонённый пакет их не тратил
+	if len(req.Locations) > h.maxItems {
+		http.Error(w, fmt.Sprintf("At most %d locations per batch", h.maxItems), http.StatusBadRequest)
+		return
+	}
+	r = withBodyLanguage(r, req.Lang)
+	if req.Units != "" {
+		if !service.ValidUnits(req.Units) {
+			http.Error(w, "units must be one of metric, imperial, standard", http.StatusBadRequest)
+			return
+		}
+		r = r.WithContext(service.WithUnits(r.Context(), req.Units))
+	}
+
+	if !h.limiter.Allow(w, r, len(req.Locations)*h.costPerItem) {
+		return
+	}
+
+	results, err := h.src.GetLocationDetailsBatch(r.Context(), req.Locations)
+	if errors.Is(err, model.ErrTooManyItems) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeResult(w, batchDetailsResponse{Results: results}, err)
+}
@@ -0,0 +1,97 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"places/internal/model"
+)
+
+// batchFunc отвечает на пакет результатами с теми же локациями
+type batchFunc struct{}
+
+func (batchFunc) GetLocationDetailsBatch(_ context.Context, locations []model.Location) ([]model.LocationResult, error) {
+	results := make([]model.LocationResult, len(locations))
+	for i, location := range locations {
+		results[i] = model.LocationResult{Location: location}
+	}
+	return results, nil
+}
+
+func batchRequest(n int) *http.Request {
+	locations := make([]model.Location, n)
+	for i := range locations {
+		locations[i] = model.Location{Name: "loc", Lat: float64(i), Lon: float64(i)}
+	}
+	body, _ := json.Marshal(batchDetailsRequest{Locations: locations})
+	req := httptest.NewRequest(http.MethodPost, "/api/locations/details:batch", strings.NewReader(string(body)))
+	req.RemoteAddr = "192.0.2.1:1234"
+	return req
+}
+
+func TestBatchCost(t *testing.T) {
+	// Лимиты по умолчанию: 2 токена в секунду, burst 40, деталь локации стоит 10
+	h := NewBatchHandler(batchFunc{}, NewRateLimiter(2, 40, nil), 10, 10)
+
+	rec := httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(3))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("3-item batch: status %d: %s", rec.Code, rec.Body)
+	}
+	var resp batchDetailsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 3 {
+		t.Errorf("results = %d, want 3", len(resp.Results))
+	}
+
+	// Пакет списал 30 токенов: на второй такой же осталось 10
+	rec = httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(2))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("next batch: status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	rec = httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(1))
+	if rec.Code != http.StatusOK {
+		t.Errorf("batch within remaining tokens: status %d", rec.Code)
+	}
+}
+
+func TestBatchCostExceedsBurst(t *testing.T) {
+	// Пакет из пяти локаций стоит 50 токенов и не пройдёт никогда: 413, а не скидка
+	h := NewBatchHandler(batchFunc{}, NewRateLimiter(2, 40, nil), 10, 10)
+
+	rec := httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(5))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status %d, want 413", rec.Code)
+	}
+	// Отклонённый пакет не тратит токены
+	rec = httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(4))
+	if rec.Code != http.StatusOK {
+		t.Errorf("full-burst batch: status %d, want 200", rec.Code)
+	}
+}
+
+func TestBatchTooManyLocations(t *testing.T) {
+	h := NewBatchHandler(batchFunc{}, NewRateLimiter(2, 40, nil), 10, 3)
+
+	rec := httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(4))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status %d, want 400", rec.Code)
+	}
+	// Отклонённый пакет не тратит токены
+	rec = httptest.NewRecorder()
+	h.GetLocationDetailsBatch(rec, batchRequest(3))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status %d, want 200", rec.Code)
+	}
+}
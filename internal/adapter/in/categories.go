@@ -0,0 +1,28 @@
+package in
+
+import (
+	"net/http"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// categoryResponse — категория таксономии с названием на языке запроса
+type categoryResponse struct {
+	model.CategoryRef
+	Parent string `json:"parent,omitempty"`
+}
+
+// Categories отдаёт таксономию категорий мест для фильтров и группировки:
+// GET /api/categories?lang=
+func Categories(w http.ResponseWriter, r *http.Request) {
+	lang := service.LanguageFromContext(r.Context())
+
+	categories := model.Categories()
+	resp := make([]categoryResponse, len(categories))
+	for i, c := range categories {
+		resp[i] = categoryResponse{CategoryRef: c.Ref(lang), Parent: c.Parent}
+	}
+
+	writeCacheableJSON(w, r, resp, searchMaxAge)
+}
@@ -0,0 +1,240 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"places/internal/model"
+)
+
+func testResult() *model.LocationResult {
+	return &model.LocationResult{
+		Location: model.Location{Name: "Академгородок", Lat: 54.84, Lon: 83.09},
+		Weather:  &model.Weather{Description: "ясно", Temp: -12.5, Units: model.UnitsMetric},
+		Places: []model.Place{
+			{
+				Xid: "museum", Name: `Музей "Сибирь" <&>`, Lat: 54.8415, Lon: 83.0951,
+				Description: "Коллекции & экспозиции", WebSite: "https://example.org/?a=1&b=2",
+				PrimaryCategory: &model.CategoryRef{ID: "sights.museum", Label: "Музей"},
+			},
+			{Xid: "cafe", Name: "Кофейня", Lat: 54.843, Lon: 83.09, Kinds: "catering.cafe"},
+		},
+	}
+}
+
+var testRoute = []model.Location{{Lat: 54.84, Lon: 83.09}, {Lat: 54.8415, Lon: 83.0951}}
+
+func TestGPX(t *testing.T) {
+	data, err := GPX(testResult(), Options{PlaceIDs: []string{"museum"}, Route: testRoute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(xml.Header)) {
+		t.Error("missing XML declaration")
+	}
+	if !strings.Contains(string(data), `xmlns="http://www.topografix.com/GPX/1/1"`) {
+		t.Error("missing GPX 1.1 namespace")
+	}
+	if strings.Contains(string(data), `<&>`) {
+		t.Errorf("special characters are not escaped: %s", data)
+	}
+
+	var doc gpxDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	// Точка самой локации и только выбранное место
+	if len(doc.Waypoints) != 2 {
+		t.Fatalf("waypoints = %+v", doc.Waypoints)
+	}
+	location, museum := doc.Waypoints[0], doc.Waypoints[1]
+	if location.Type != "location" || location.Description != "ясно, -12.5°C" {
+		t.Errorf("location waypoint = %+v", location)
+	}
+	if museum.Name != `Музей "Сибирь" <&>` || museum.Type != "Музей" || museum.Lat != 54.8415 || museum.Lon != 83.0951 {
+		t.Errorf("place waypoint = %+v", museum)
+	}
+	if len(museum.Links) != 1 || museum.Links[0].Href != "https://example.org/?a=1&b=2" {
+		t.Errorf("links = %+v", museum.Links)
+	}
+	if len(doc.Tracks) != 1 || len(doc.Tracks[0].Points) != 2 || doc.Tracks[0].Points[1].Lon != 83.0951 {
+		t.Errorf("tracks = %+v", doc.Tracks)
+	}
+}
+
+func TestGPXWithoutRoute(t *testing.T) {
+	data, err := GPX(testResult(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc gpxDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Waypoints) != 3 || len(doc.Tracks) != 0 {
+		t.Errorf("waypoints = %d, tracks = %d", len(doc.Waypoints), len(doc.Tracks))
+	}
+	if doc.Waypoints[2].Type != "catering.cafe" {
+		t.Errorf("place without category type = %q", doc.Waypoints[2].Type)
+	}
+}
+
+func TestKML(t *testing.T) {
+	data, err := KML(testResult(), Options{PlaceIDs: []string{"cafe", "unknown"}, Route: testRoute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `xmlns="http://www.opengis.net/kml/2.2"`) {
+		t.Error("missing KML 2.2 namespace")
+	}
+
+	var doc kmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	// Локация, выбранное место и линия маршрута; неизвестный xid пропускается
+	if len(doc.Placemarks) != 3 {
+		t.Fatalf("placemarks = %+v", doc.Placemarks)
+	}
+	cafe, route := doc.Placemarks[1], doc.Placemarks[2]
+	if cafe.ID != "cafe" || cafe.Point == nil || cafe.Point.Coordinates != "83.09,54.843" {
+		t.Errorf("place placemark = %+v", cafe)
+	}
+	if route.LineString == nil || route.LineString.Coordinates != "83.09,54.84 83.0951,54.8415" {
+		t.Errorf("route placemark = %+v", route)
+	}
+	if route.Point != nil {
+		t.Error("route placemark has a point")
+	}
+}
+
+func TestKMLEscaping(t *testing.T) {
+	data, err := KML(testResult(), Options{PlaceIDs: []string{"museum"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "<&>") || strings.Contains(string(data), "a=1&b=2") {
+		t.Errorf("special characters are not escaped: %s", data)
+	}
+
+	var doc kmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	museum := doc.Placemarks[1]
+	if museum.Name != `Музей "Сибирь" <&>` || museum.Description != "Коллекции & экспозиции" {
+		t.Errorf("placemark = %+v", museum)
+	}
+	want := []kmlData{{Name: "category", Value: "Музей"}, {Name: "website", Value: "https://example.org/?a=1&b=2"}}
+	if len(museum.Data) != 2 || museum.Data[0] != want[0] || museum.Data[1] != want[1] {
+		t.Errorf("data = %+v", museum.Data)
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *model.LocationResult
+		// kinds — свойство kind каждого объекта по порядку
+		kinds  []string
+		coords [][]float64
+		check  func(t *testing.T, features []Feature)
+	}{
+		{
+			name:   "empty result",
+			result: &model.LocationResult{},
+			kinds:  []string{"location"},
+			coords: [][]float64{{0, 0}},
+			check: func(t *testing.T, features []Feature) {
+				// Без погоды в свойствах локации только вид и имя
+				if len(features[0].Properties) != 2 {
+					t.Errorf("properties = %v", features[0].Properties)
+				}
+			},
+		},
+		{
+			name:   "places without clusters",
+			result: testResult(),
+			kinds:  []string{"location", "place", "place"},
+			coords: [][]float64{{83.09, 54.84}, {83.0951, 54.8415}, {83.09, 54.843}},
+			check: func(t *testing.T, features []Feature) {
+				if features[0].Properties["temp"] != -12.5 || features[0].Properties["units"] != model.UnitsMetric {
+					t.Errorf("location properties = %v", features[0].Properties)
+				}
+				museum, cafe := features[1], features[2]
+				if museum.ID != "museum" || museum.Properties["category"] != "sights.museum" || museum.Properties["website"] != "https://example.org/?a=1&b=2" {
+					t.Errorf("museum = %+v", museum)
+				}
+				// Пустые поля и нулевое расстояние не попадают в свойства
+				for _, key := range []string{"category", "distance", "description", "categories", "reasons"} {
+					if _, ok := cafe.Properties[key]; ok {
+						t.Errorf("cafe has %s: %v", key, cafe.Properties[key])
+					}
+				}
+			},
+		},
+		{
+			name: "clusters",
+			result: &model.LocationResult{
+				Location: model.Location{Lat: 55, Lon: 83},
+				Clusters: []model.PlaceCluster{{
+					ID: "c1", Lat: 55.01, Lon: 83.02, Count: 3, Xids: []string{"a", "b", "c"},
+					Categories: []model.ClusterCategory{{CategoryRef: model.CategoryRef{ID: "sights"}, Count: 2}, {CategoryRef: model.CategoryRef{ID: "food"}, Count: 1}},
+				}},
+			},
+			kinds:  []string{"location", "cluster"},
+			coords: [][]float64{{83, 55}, {83.02, 55.01}},
+			check: func(t *testing.T, features []Feature) {
+				want := map[string]any{"kind": "cluster", "count": 3, "xids": "a,b,c", "categories": "sights:2,food:1"}
+				if features[1].ID != "c1" || !reflect.DeepEqual(features[1].Properties, want) {
+					t.Errorf("cluster = %+v", features[1])
+				}
+			},
+		},
+		{
+			name: "boundary coordinates",
+			result: &model.LocationResult{
+				Location: model.Location{Lat: -90, Lon: -180},
+				Places:   []model.Place{{Xid: "pole", Lat: 90, Lon: 180}},
+			},
+			kinds:  []string{"location", "place"},
+			coords: [][]float64{{-180, -90}, {180, 90}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := GeoJSON(tt.result)
+			if collection.Type != "FeatureCollection" || len(collection.Features) != len(tt.kinds) {
+				t.Fatalf("collection = %+v", collection)
+			}
+			for i, feature := range collection.Features {
+				if feature.Type != "Feature" || feature.Geometry.Type != "Point" || feature.Properties["kind"] != tt.kinds[i] {
+					t.Errorf("feature %d = %+v", i, feature)
+				}
+				// RFC 7946: долгота идёт первой
+				if !reflect.DeepEqual(feature.Geometry.Coordinates, tt.coords[i]) {
+					t.Errorf("feature %d coordinates = %v, want %v", i, feature.Geometry.Coordinates, tt.coords[i])
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, collection.Features)
+			}
+
+			// Коллекция кодируется в валидный JSON с массивом features
+			data, err := json.Marshal(collection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded struct {
+				Features []json.RawMessage `json:"features"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Features) != len(tt.kinds) {
+				t.Errorf("encoded = %s, err = %v", data, err)
+			}
+		})
+	}
+}
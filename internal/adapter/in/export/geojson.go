@@ -0,0 +1,146 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"places/internal/model"
+)
+
+// GeoJSONContentType — тип содержимого GeoJSON из RFC 7946
+const GeoJSONContentType = "application/geo+json"
+
+// FeatureCollection — корневой объект GeoJSON
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry поддерживает только точки: координаты в порядке [lon, lat]
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// GeoJSON превращает результат по локации в FeatureCollection.
+// Первый объект — сама локация с погодой в свойствах, за ним по объекту на каждое место
+// и на каждую группу мест.
+// Свойства плоские, чтобы их без преобразований видели QGIS и Leaflet
+func GeoJSON(result *model.LocationResult) FeatureCollection {
+	collection := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]Feature, 0, len(result.Places)+len(result.Clusters)+1),
+	}
+
+	collection.Features = append(collection.Features, locationFeature(result.Location, result.Weather))
+	for _, place := range result.Places {
+		collection.Features = append(collection.Features, placeFeature(place))
+	}
+	for _, cluster := range result.Clusters {
+		collection.Features = append(collection.Features, clusterFeature(cluster))
+	}
+
+	return collection
+}
+
+func locationFeature(location model.Location, weather *model.Weather) Feature {
+	properties := map[string]any{
+		"kind": "location",
+		"name": location.Name,
+	}
+	setString(properties, "country", location.Country)
+	setString(properties, "state", location.State)
+
+	if weather != nil {
+		properties["temp"] = weather.Temp
+		properties["feels_like"] = weather.FeelsLike
+		properties["weather"] = weather.Description
+		properties["humidity"] = weather.Humidity
+		properties["wind_speed"] = weather.WindSpeed
+		properties["icon"] = weather.Icon
+		properties["units"] = weather.Units
+	}
+
+	return Feature{
+		Type:       "Feature",
+		Geometry:   point(location.Lat, location.Lon),
+		Properties: properties,
+	}
+}
+
+func placeFeature(place model.Place) Feature {
+	properties := map[string]any{
+		"kind":  "place",
+		"xid":   place.Xid,
+		"name":  place.Name,
+		"kinds": place.Kinds,
+		"units": place.Units,
+		"score": place.Score,
+	}
+	if place.Distance != 0 {
+		properties["distance"] = place.Distance
+	}
+	setString(properties, "description", place.Description)
+	setString(properties, "image", place.Image)
+	setString(properties, "website", place.WebSite)
+	setString(properties, "wikipedia", place.Wikipedia)
+	setString(properties, "opening_hours", place.OpeningHours)
+	if place.PrimaryCategory != nil {
+		properties["category"] = place.PrimaryCategory.ID
+		properties["category_label"] = place.PrimaryCategory.Label
+	}
+	if len(place.Categories) > 0 {
+		ids := make([]string, len(place.Categories))
+		for i, category := range place.Categories {
+			ids[i] = category.ID
+		}
+		properties["categories"] = strings.Join(ids, ",")
+	}
+	if len(place.Reasons) > 0 {
+		properties["reasons"] = strings.Join(place.Reasons, ",")
+	}
+
+	return Feature{
+		Type:       "Feature",
+		ID:         place.Xid,
+		Geometry:   point(place.Lat, place.Lon),
+		Properties: properties,
+	}
+}
+
+// clusterFeature — группа мест; разбивка по категориям в виде "sights:3,food:1"
+func clusterFeature(cluster model.PlaceCluster) Feature {
+	categories := make([]string, len(cluster.Categories))
+	for i, category := range cluster.Categories {
+		categories[i] = fmt.Sprintf("%s:%d", category.ID, category.Count)
+	}
+
+	return Feature{
+		Type:     "Feature",
+		ID:       cluster.ID,
+		Geometry: point(cluster.Lat, cluster.Lon),
+		Properties: map[string]any{
+			"kind":       "cluster",
+			"count":      cluster.Count,
+			"xids":       strings.Join(cluster.Xids, ","),
+			"categories": strings.Join(categories, ","),
+		},
+	}
+}
+
+func point(lat, lon float64) Geometry {
+	return Geometry{Type: "Point", Coordinates: []float64{lon, lat}}
+}
+
+func setString(properties map[string]any, key, value string) {
+	if value != "" {
+		properties[key] = value
+	}
+}
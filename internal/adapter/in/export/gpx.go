@@ -0,0 +1,87 @@
+package export
+
+import (
+	"encoding/xml"
+
+	"places/internal/model"
+)
+
+// GPXContentType — тип содержимого GPX 1.1
+const GPXContentType = "application/gpx+xml"
+
+type gpxDocument struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Name      string        `xml:"metadata>name,omitempty"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+	Tracks    []gpxTrack    `xml:"trk"`
+}
+
+type gpxWaypoint struct {
+	Lat         float64   `xml:"lat,attr"`
+	Lon         float64   `xml:"lon,attr"`
+	Name        string    `xml:"name,omitempty"`
+	Description string    `xml:"desc,omitempty"`
+	Links       []gpxLink `xml:"link"`
+	Type        string    `xml:"type,omitempty"`
+}
+
+type gpxLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type gpxTrack struct {
+	Name   string        `xml:"name,omitempty"`
+	Points []gpxWaypoint `xml:"trkseg>trkpt"`
+}
+
+// GPX выгружает локацию и места точками маршрута (wpt), а маршрут — треком
+func GPX(result *model.LocationResult, opts Options) ([]byte, error) {
+	doc := gpxDocument{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "places",
+		Name:    result.Location.Name,
+	}
+
+	doc.Waypoints = append(doc.Waypoints, gpxWaypoint{
+		Lat:         result.Location.Lat,
+		Lon:         result.Location.Lon,
+		Name:        result.Location.Name,
+		Description: weatherSummary(result.Weather),
+		Type:        "location",
+	})
+	for _, place := range selectPlaces(result.Places, opts.PlaceIDs) {
+		wpt := gpxWaypoint{
+			Lat:         place.Lat,
+			Lon:         place.Lon,
+			Name:        place.Name,
+			Description: place.Description,
+			Type:        categoryLabel(place),
+		}
+		if place.WebSite != "" {
+			wpt.Links = append(wpt.Links, gpxLink{Href: place.WebSite})
+		}
+		doc.Waypoints = append(doc.Waypoints, wpt)
+	}
+
+	if len(opts.Route) > 0 {
+		track := gpxTrack{Name: result.Location.Name}
+		for _, point := range opts.Route {
+			track.Points = append(track.Points, gpxWaypoint{Lat: point.Lat, Lon: point.Lon, Name: point.Name})
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	return marshalXML(doc)
+}
+
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
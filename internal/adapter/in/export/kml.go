@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"places/internal/model"
+)
+
+// KMLContentType — тип содержимого KML 2.2
+const KMLContentType = "application/vnd.google-earth.kml+xml"
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Xmlns      string         `xml:"xmlns,attr"`
+	Name       string         `xml:"Document>name,omitempty"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID          string       `xml:"id,attr,omitempty"`
+	Name        string       `xml:"name"`
+	Description string       `xml:"description,omitempty"`
+	Data        []kmlData    `xml:"ExtendedData>Data,omitempty"`
+	Point       *kmlGeometry `xml:"Point,omitempty"`
+	LineString  *kmlGeometry `xml:"LineString,omitempty"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlGeometry struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// KML выгружает локацию и места метками (Placemark), а маршрут — линией
+func KML(result *model.LocationResult, opts Options) ([]byte, error) {
+	doc := kmlDocument{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+		Name:  result.Location.Name,
+	}
+
+	doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+		Name:        result.Location.Name,
+		Description: weatherSummary(result.Weather),
+		Data:        []kmlData{{Name: "category", Value: "location"}},
+		Point:       &kmlGeometry{Coordinates: kmlCoordinates(result.Location.Lat, result.Location.Lon)},
+	})
+	for _, place := range selectPlaces(result.Places, opts.PlaceIDs) {
+		placemark := kmlPlacemark{
+			ID:          place.Xid,
+			Name:        place.Name,
+			Description: place.Description,
+			Data:        []kmlData{{Name: "category", Value: categoryLabel(place)}},
+			Point:       &kmlGeometry{Coordinates: kmlCoordinates(place.Lat, place.Lon)},
+		}
+		if place.WebSite != "" {
+			placemark.Data = append(placemark.Data, kmlData{Name: "website", Value: place.WebSite})
+		}
+		doc.Placemarks = append(doc.Placemarks, placemark)
+	}
+
+	if len(opts.Route) > 0 {
+		coordinates := make([]string, 0, len(opts.Route))
+		for _, point := range opts.Route {
+			coordinates = append(coordinates, kmlCoordinates(point.Lat, point.Lon))
+		}
+		doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+			Name:       "route",
+			Data:       []kmlData{{Name: "category", Value: "route"}},
+			LineString: &kmlGeometry{Coordinates: strings.Join(coordinates, " ")},
+		})
+	}
+
+	return marshalXML(doc)
+}
+
+// kmlCoordinates — в KML сначала долгота, затем широта
+func kmlCoordinates(lat, lon float64) string {
+	return strconv.FormatFloat(lon, 'f', -1, 64) + "," + strconv.FormatFloat(lat, 'f', -1, 64)
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+
+	"places/internal/model"
+)
+
+// Options управляет содержимым GPX и KML
+type Options struct {
+	// PlaceIDs оставляет в выгрузке только выбранные места; пусто — все
+	PlaceIDs []string
+	// Route — точки маршрута, построенного клиентом; выгружаются как есть, без прокладки по дорогам
+	Route []model.Location
+}
+
+// selectPlaces отбирает места по идентификаторам, сохраняя порядок результата
+func selectPlaces(places []model.Place, ids []string) []model.Place {
+	if len(ids) == 0 {
+		return places
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	selected := make([]model.Place, 0, len(ids))
+	for _, place := range places {
+		if wanted[place.Xid] {
+			selected = append(selected, place)
+		}
+	}
+	return selected
+}
+
+// categoryLabel — название основной категории, а для мест без неё исходные категории провайдера
+func categoryLabel(place model.Place) string {
+	if place.PrimaryCategory != nil {
+		return place.PrimaryCategory.Label
+	}
+	return place.Kinds
+}
+
+// weatherSummary — краткое описание погоды для точки самой локации
+func weatherSummary(weather *model.Weather) string {
+	if weather == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s, %.1f%s", weather.Description, weather.Temp, temperatureUnit(weather.Units))
+}
+
+func temperatureUnit(units string) string {
+	switch units {
+	case model.UnitsImperial:
+		return "°F"
+	case model.UnitsStandard:
+		return " K"
+	default:
+		return "°C"
+	}
+}
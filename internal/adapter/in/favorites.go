@@ -0,0 +1,150 @@
+package in
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+type FavoritesHandler struct {
+	src service.FavoritesService
+}
+
+func NewFavoritesHandler(service service.FavoritesService) *FavoritesHandler {
+	return &FavoritesHandler{
+		src: service,
+	}
+}
+
+type renameListRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *FavoritesHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.src.Lists(r.Context(), user)
+	writeResult(w, lists, err)
+}
+
+func (h *FavoritesHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.src.List(r.Context(), user, mux.Vars(r)["list"])
+	writeResult(w, list, err)
+}
+
+func (h *FavoritesHandler) RenameList(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req renameListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.src.RenameList(r.Context(), user, mux.Vars(r)["list"], req.Name); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FavoritesHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.src.DeleteList(r.Context(), user, mux.Vars(r)["list"]); err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FavoritesHandler) AddLocation(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var location model.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.src.AddLocation(r.Context(), user, mux.Vars(r)["list"], location)
+	writeResult(w, list, err)
+}
+
+func (h *FavoritesHandler) RemoveLocation(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	lat, err := strconv.ParseFloat(vars["lat"], 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lon, err := strconv.ParseFloat(vars["lon"], 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.src.RemoveLocation(r.Context(), user, vars["list"], lat, lon)
+	writeResult(w, list, err)
+}
+
+func (h *FavoritesHandler) AddPlace(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var place model.Place
+	if err := json.NewDecoder(r.Body).Decode(&place); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if place.Xid == "" {
+		http.Error(w, "Place xid is required", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.src.AddPlace(r.Context(), user, mux.Vars(r)["list"], place)
+	writeResult(w, list, err)
+}
+
+func (h *FavoritesHandler) RemovePlace(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	list, err := h.src.RemovePlace(r.Context(), user, vars["list"], vars["xid"])
+	writeResult(w, list, err)
+}
@@ -0,0 +1,55 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"places/internal/service"
+)
+
+//go:embed schema.graphql
+var schema string
+
+const (
+	// maxDepth — самая глубокая вложенность схемы: location.forecast.items.weather.temp
+	maxDepth = 5
+	// maxParallelism — сколько резолверов одного запроса работают одновременно
+	maxParallelism = 8
+	// queryBudget — сколько обращений к сервису допускает один запрос. Запрос
+	// стоит столько же, сколько детали локации: погода, прогноз, места и детали
+	// каждого из них, поэтому псевдонимы не дают получить больше за ту же цену
+	queryBudget = 64
+)
+
+// errBudgetExceeded возвращается полям, на которые не хватило бюджета запроса
+var errBudgetExceeded = errors.New("query requests too much data: split it into several queries")
+
+type budgetKey struct{}
+
+// NewHandler создает HTTP handler для /graphql поверх сервиса
+func NewHandler(service service.Service) http.Handler {
+	s := graphql.MustParseSchema(schema, &queryResolver{src: service},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	h := &relay.Handler{Schema: s}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget := new(atomic.Int32)
+		budget.Store(queryBudget)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), budgetKey{}, budget)))
+	})
+}
+
+// spend списывает одно обращение к сервису из бюджета запроса
+func spend(ctx context.Context) error {
+	budget, ok := ctx.Value(budgetKey{}).(*atomic.Int32)
+	if ok && budget.Add(-1) < 0 {
+		return errBudgetExceeded
+	}
+	return nil
+}
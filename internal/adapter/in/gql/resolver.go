@@ -0,0 +1,300 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+/*
+	Резолверы ленивые: запрос к провайдеру выполняется только если
+	соответствующее поле выбрано в запросе. Каждое обращение к сервису
+	списывается из бюджета запроса
+*/
+
+type queryResolver struct {
+	src service.Service
+}
+
+func (q *queryResolver) SearchLocations(ctx context.Context, args struct{ Query string }) ([]*locationResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	locations, err := q.src.SearchLocations(ctx, args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*locationResolver, 0, len(locations))
+	for _, l := range locations {
+		resolvers = append(resolvers, &locationResolver{src: q.src, location: l})
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) Location(args struct {
+	Lat  float64
+	Lon  float64
+	Name *string
+}) *locationResolver {
+	location := model.Location{Lat: args.Lat, Lon: args.Lon}
+	if args.Name != nil {
+		location.Name = *args.Name
+	}
+	return &locationResolver{src: q.src, location: location}
+}
+
+type locationResolver struct {
+	src      service.Service
+	location model.Location
+
+	placesOnce sync.Once
+	places     []*placeResolver
+	placesErr  error
+}
+
+func (l *locationResolver) Name() string {
+	return l.location.Name
+}
+
+func (l *locationResolver) Lat() float64 {
+	return l.location.Lat
+}
+
+func (l *locationResolver) Lon() float64 {
+	return l.location.Lon
+}
+
+func (l *locationResolver) Country() *string {
+	return optional(l.location.Country)
+}
+
+func (l *locationResolver) State() *string {
+	return optional(l.location.State)
+}
+
+func (l *locationResolver) Weather(ctx context.Context) (*weatherResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	w, err := l.src.GetWeather(ctx, l.location)
+	if err != nil || w == nil {
+		return nil, err
+	}
+	return &weatherResolver{weather: *w}, nil
+}
+
+func (l *locationResolver) Forecast(ctx context.Context) (*forecastResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	f, err := l.src.GetForecast(ctx, l.location)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	return &forecastResolver{forecast: *f}, nil
+}
+
+func (l *locationResolver) Places(ctx context.Context) ([]*placeResolver, error) {
+	l.placesOnce.Do(func() {
+		if l.placesErr = spend(ctx); l.placesErr != nil {
+			return
+		}
+		ps, err := l.src.GetPlaces(ctx, l.location)
+		if err != nil {
+			l.placesErr = err
+			return
+		}
+		l.places = make([]*placeResolver, 0, len(ps))
+		for _, p := range ps {
+			l.places = append(l.places, &placeResolver{src: l.src, place: p})
+		}
+	})
+	return l.places, l.placesErr
+}
+
+type weatherResolver struct {
+	weather model.Weather
+}
+
+func (w *weatherResolver) Temp() float64 {
+	return w.weather.Temp
+}
+
+func (w *weatherResolver) FeelsLike() float64 {
+	return w.weather.FeelsLike
+}
+
+func (w *weatherResolver) Description() string {
+	return w.weather.Description
+}
+
+func (w *weatherResolver) Humidity() int32 {
+	return int32(w.weather.Humidity)
+}
+
+func (w *weatherResolver) WindSpeed() float64 {
+	return w.weather.WindSpeed
+}
+
+func (w *weatherResolver) Icon() string {
+	return w.weather.Icon
+}
+
+func (w *weatherResolver) Units() string {
+	return w.weather.Units
+}
+
+type forecastResolver struct {
+	forecast model.Forecast
+}
+
+func (f *forecastResolver) Items() []*forecastItemResolver {
+	items := make([]*forecastItemResolver, 0, len(f.forecast.Items))
+	for _, item := range f.forecast.Items {
+		items = append(items, &forecastItemResolver{item: item})
+	}
+	return items
+}
+
+type forecastItemResolver struct {
+	item model.ForecastItem
+}
+
+func (f *forecastItemResolver) Time() string {
+	return f.item.Time.Format(time.RFC3339)
+}
+
+func (f *forecastItemResolver) Weather() *weatherResolver {
+	return &weatherResolver{weather: f.item.Weather}
+}
+
+// placeResolver отдаёт поля из списка мест сразу, а за описанием,
+// картинкой и ссылками ходит в GetPlaceDetails только при первом обращении
+type placeResolver struct {
+	src   service.Service
+	place model.Place
+
+	detailsOnce sync.Once
+	details     model.Place
+	detailsErr  error
+}
+
+// loadDetails без деталей у провайдера отдаёт место из списка,
+// а ошибку возвращает только при исчерпанном бюджете запроса
+func (p *placeResolver) loadDetails(ctx context.Context) (model.Place, error) {
+	p.detailsOnce.Do(func() {
+		p.details = p.place
+		if p.detailsErr = spend(ctx); p.detailsErr != nil {
+			return
+		}
+		if d, err := p.src.GetPlaceDetails(ctx, p.place.Xid); err == nil && d != nil {
+			p.details = *d
+		}
+	})
+	return p.details, p.detailsErr
+}
+
+func (p *placeResolver) Xid() graphql.ID {
+	return graphql.ID(p.place.Xid)
+}
+
+func (p *placeResolver) Name() string {
+	return p.place.Name
+}
+
+func (p *placeResolver) Kinds() string {
+	return p.place.Kinds
+}
+
+func (p *placeResolver) Lat() float64 {
+	return p.place.Lat
+}
+
+func (p *placeResolver) Lon() float64 {
+	return p.place.Lon
+}
+
+func (p *placeResolver) Units() string {
+	return p.place.Units
+}
+
+func (p *placeResolver) Categories() []*categoryResolver {
+	categories := make([]*categoryResolver, len(p.place.Categories))
+	for i, ref := range p.place.Categories {
+		categories[i] = &categoryResolver{ref: ref}
+	}
+	return categories
+}
+
+func (p *placeResolver) PrimaryCategory() *categoryResolver {
+	if p.place.PrimaryCategory == nil {
+		return nil
+	}
+	return &categoryResolver{ref: *p.place.PrimaryCategory}
+}
+
+func (p *placeResolver) Distance() *float64 {
+	if p.place.Distance == 0 {
+		return nil
+	}
+	return &p.place.Distance
+}
+
+func (p *placeResolver) Score() float64 {
+	return p.place.Score
+}
+
+func (p *placeResolver) Reasons() []string {
+	if p.place.Reasons == nil {
+		return []string{}
+	}
+	return p.place.Reasons
+}
+
+func (p *placeResolver) Description(ctx context.Context) (*string, error) {
+	details, err := p.loadDetails(ctx)
+	return optional(details.Description), err
+}
+
+func (p *placeResolver) Image(ctx context.Context) (*string, error) {
+	details, err := p.loadDetails(ctx)
+	return optional(details.Image), err
+}
+
+func (p *placeResolver) Website(ctx context.Context) (*string, error) {
+	details, err := p.loadDetails(ctx)
+	return optional(details.WebSite), err
+}
+
+func (p *placeResolver) Wikipedia(ctx context.Context) (*string, error) {
+	details, err := p.loadDetails(ctx)
+	return optional(details.Wikipedia), err
+}
+
+type categoryResolver struct {
+	ref model.CategoryRef
+}
+
+func (c *categoryResolver) ID() graphql.ID {
+	return graphql.ID(c.ref.ID)
+}
+
+func (c *categoryResolver) Label() string {
+	return c.ref.Label
+}
+
+func (c *categoryResolver) Icon() string {
+	return c.ref.Icon
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,190 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// countingService отдаёт три места и считает обращения к каждому методу
+type countingService struct {
+	service.Service
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCountingService() *countingService {
+	return &countingService{calls: make(map[string]int)}
+}
+
+func (s *countingService) count(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method]++
+}
+
+func (s *countingService) called(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *countingService) GetWeather(context.Context, model.Location) (*model.Weather, error) {
+	s.count("GetWeather")
+	return &model.Weather{Temp: 20, Units: "metric"}, nil
+}
+
+func (s *countingService) GetForecast(context.Context, model.Location) (*model.Forecast, error) {
+	s.count("GetForecast")
+	return &model.Forecast{Items: []model.ForecastItem{{Weather: model.Weather{Temp: 18}}}}, nil
+}
+
+func (s *countingService) GetPlaces(context.Context, model.Location) ([]model.Place, error) {
+	s.count("GetPlaces")
+	return []model.Place{{Xid: "a", Name: "A"}, {Xid: "b", Name: "B"}, {Xid: "c", Name: "C"}}, nil
+}
+
+func (s *countingService) GetPlaceDetails(_ context.Context, xid string) (*model.Place, error) {
+	s.count("GetPlaceDetails")
+	return &model.Place{Xid: xid, Name: strings.ToUpper(xid), Description: "about " + xid, Image: "https://img/" + xid}, nil
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, h http.Handler, q string) graphqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": q})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	var resp graphqlResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("status %d: %v", rec.Code, err)
+	}
+	return resp
+}
+
+func TestPlaceDetailsAreLazy(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      string
+		wantDetails int
+	}{
+		{"list fields only", "xid name lat lon", 0},
+		{"one detail field", "xid description", 3},
+		// Несколько полей деталей одного места — одно обращение
+		{"several detail fields", "description image website wikipedia", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newCountingService()
+			resp := query(t, NewHandler(src), `{ location(lat: 55.75, lon: 37.61) { places { `+tt.fields+` } } }`)
+			if len(resp.Errors) != 0 {
+				t.Fatalf("errors = %+v", resp.Errors)
+			}
+			if got := src.called("GetPlaceDetails"); got != tt.wantDetails {
+				t.Errorf("GetPlaceDetails calls = %d, want %d", got, tt.wantDetails)
+			}
+			if got := src.called("GetPlaces"); got != 1 {
+				t.Errorf("GetPlaces calls = %d, want 1", got)
+			}
+			if src.called("GetWeather") != 0 {
+				t.Error("weather loaded without being selected")
+			}
+		})
+	}
+
+	src := newCountingService()
+	resp := query(t, NewHandler(src), `{ location(lat: 55.75, lon: 37.61) { places { xid description } } }`)
+	var data struct {
+		Location struct {
+			Places []struct {
+				Xid         string
+				Description *string
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Location.Places) != 3 || data.Location.Places[1].Description == nil || *data.Location.Places[1].Description != "about b" {
+		t.Errorf("data = %s", resp.Data)
+	}
+}
+
+func TestQueryBudget(t *testing.T) {
+	aliases := func(n int) string {
+		var b strings.Builder
+		b.WriteString("{")
+		for i := range n {
+			fmt.Fprintf(&b, " w%d: location(lat: 55.75, lon: 37.61) { weather { temp } }", i)
+		}
+		b.WriteString(" }")
+		return b.String()
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantErrors  bool
+		wantWeather int
+	}{
+		{"within budget", aliases(queryBudget), false, queryBudget},
+		// Псевдонимы сверх бюджета не доходят до сервиса
+		{"aliases over budget", aliases(queryBudget + 3), true, queryBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newCountingService()
+			resp := query(t, NewHandler(src), tt.query)
+			if (len(resp.Errors) != 0) != tt.wantErrors {
+				t.Fatalf("errors = %+v", resp.Errors)
+			}
+			if tt.wantErrors && resp.Errors[0].Message != errBudgetExceeded.Error() {
+				t.Errorf("error = %q", resp.Errors[0].Message)
+			}
+			if got := src.called("GetWeather"); got != tt.wantWeather {
+				t.Errorf("GetWeather calls = %d, want %d", got, tt.wantWeather)
+			}
+		})
+	}
+}
+
+func TestQueryBudgetCountsDetails(t *testing.T) {
+	// Каждый псевдоним — список мест и детали трёх мест, то есть четыре обращения
+	var b strings.Builder
+	b.WriteString("{")
+	for i := range queryBudget/4 + 1 {
+		fmt.Fprintf(&b, " p%d: location(lat: 55.75, lon: 37.61) { places { description } }", i)
+	}
+	b.WriteString(" }")
+
+	src := newCountingService()
+	resp := query(t, NewHandler(src), b.String())
+	if len(resp.Errors) == 0 {
+		t.Fatal("expected budget errors")
+	}
+	if got := src.called("GetPlaces") + src.called("GetPlaceDetails"); got != queryBudget {
+		t.Errorf("service calls = %d, want %d", got, queryBudget)
+	}
+}
+
+func TestQueryDepth(t *testing.T) {
+	// Самый глубокий путь схемы укладывается в maxDepth
+	resp := query(t, NewHandler(newCountingService()), `{ location(lat: 1, lon: 1) { forecast { items { weather { temp } } } } }`)
+	if len(resp.Errors) != 0 || !strings.Contains(string(resp.Data), `"temp":18`) {
+		t.Errorf("data = %s, errors = %+v", resp.Data, resp.Errors)
+	}
+}
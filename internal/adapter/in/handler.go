@@ -1,9 +1,17 @@
 package in
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/mux"
+	"places/internal/adapter/in/export"
 	"places/internal/model"
 	"places/internal/service"
 )
@@ -20,10 +28,15 @@ func NewHandler(service service.Service) *Handler {
 
 type searchRequest struct {
 	Query string `json:"query"`
+	Lang  string `json:"lang,omitempty"`
 }
 
 type locationDetailsRequest struct {
 	Location model.Location `json:"location"`
+	Lang     string         `json:"lang,omitempty"`
+	Units    string         `json:"units,omitempty"`
+	// Zoom — уровень масштаба карты, при котором близкие места объединяются в группы
+	Zoom *int `json:"zoom,omitempty"`
 }
 
 func (h *Handler) SearchLocations(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +55,7 @@ func (h *Handler) SearchLocations(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Query is required", http.StatusBadRequest)
 		return
 	}
+	r = withBodyLanguage(r, req.Lang)
 
 	locations, err := h.src.SearchLocations(r.Context(), req.Query)
 	if err != nil {
@@ -67,14 +81,319 @@ func (h *Handler) GetLocationDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	r = withBodyLanguage(r, req.Lang)
+	if req.Units != "" {
+		if !service.ValidUnits(req.Units) {
+			http.Error(w, "units must be one of metric, imperial, standard", http.StatusBadRequest)
+			return
+		}
+		r = r.WithContext(service.WithUnits(r.Context(), req.Units))
+	}
+	if req.Zoom != nil && !validZoom(*req.Zoom) {
+		http.Error(w, zoomError, http.StatusBadRequest)
+		return
+	}
+
 	result, err := h.src.GetLocationDetails(r.Context(), req.Location)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if req.Zoom != nil {
+		clusterPlaces(r, result, *req.Zoom)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
+
+// Время жизни ответов GET-эндпоинтов в кешах браузеров и прокси.
+// Геокодинг почти не меняется, а погода устаревает за несколько минут
+const (
+	searchMaxAge  = 3600
+	detailsMaxAge = 300
+)
+
+// SearchLocationsByQuery — кешируемый GET-вариант SearchLocations: /api/search?q=
+func (h *Handler) SearchLocationsByQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Query is required", http.StatusBadRequest)
+		return
+	}
+
+	locations, err := h.src.SearchLocations(r.Context(), query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeCacheableJSON(w, r, locations, searchMaxAge)
+}
+
+// GetLocationDetailsByCoordinates — кешируемый GET-вариант GetLocationDetails:
+// /api/locations/{lat},{lon}/details?name=&zoom=
+func (h *Handler) GetLocationDetailsByCoordinates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	location, err := locationFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoom, clustered, err := zoomFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.src.GetLocationDetails(r.Context(), location)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if clustered {
+		clusterPlaces(r, result, zoom)
+	}
+
+	// Клиенты ГИС могут запросить тот же ресурс в виде GeoJSON
+	if acceptsGeoJSON(r.Header.Get("Accept")) {
+		writeCacheable(w, r, export.GeoJSON(result), detailsMaxAge, export.GeoJSONContentType)
+		return
+	}
+	writeCacheableJSON(w, r, result, detailsMaxAge)
+}
+
+// GetLocationDetailsGeoJSON отдаёт детали локации как FeatureCollection:
+// /api/locations/{lat},{lon}/details.geojson?name=&zoom=
+func (h *Handler) GetLocationDetailsGeoJSON(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	location, err := locationFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoom, clustered, err := zoomFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.src.GetLocationDetails(r.Context(), location)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if clustered {
+		clusterPlaces(r, result, zoom)
+	}
+
+	writeCacheable(w, r, export.GeoJSON(result), detailsMaxAge, export.GeoJSONContentType)
+}
+
+// GetLocationDetailsGPX выгружает локацию и места в GPX:
+// /api/locations/{lat},{lon}/details.gpx?places=&route=
+func (h *Handler) GetLocationDetailsGPX(w http.ResponseWriter, r *http.Request) {
+	h.exportLocationDetails(w, r, export.GPX, export.GPXContentType, "places.gpx")
+}
+
+// GetLocationDetailsKML выгружает локацию и места в KML:
+// /api/locations/{lat},{lon}/details.kml?places=&route=
+func (h *Handler) GetLocationDetailsKML(w http.ResponseWriter, r *http.Request) {
+	h.exportLocationDetails(w, r, export.KML, export.KMLContentType, "places.kml")
+}
+
+// exportLocationDetails — общая часть выгрузок для навигаторов.
+// places — список xid через запятую, route — точки маршрута "lat,lon" через точку с запятой
+func (h *Handler) exportLocationDetails(
+	w http.ResponseWriter,
+	r *http.Request,
+	render func(*model.LocationResult, export.Options) ([]byte, error),
+	contentType, filename string,
+) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	location, err := locationFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var opts export.Options
+	if places := r.URL.Query().Get("places"); places != "" {
+		opts.PlaceIDs = strings.Split(places, ",")
+	}
+	if route := r.URL.Query().Get("route"); route != "" {
+		opts.Route, err = parseRoute(route)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.src.GetLocationDetails(r.Context(), location)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := render(result, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	writeCacheableBody(w, r, body, detailsMaxAge, contentType)
+}
+
+// parseRoute разбирает точки маршрута вида "lat,lon;lat,lon"
+func parseRoute(route string) ([]model.Location, error) {
+	var points []model.Location
+	for _, pair := range strings.Split(route, ";") {
+		latStr, lonStr, ok := strings.Cut(pair, ",")
+		if !ok {
+			return nil, fmt.Errorf("invalid route point %q", pair)
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route point %q", pair)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route point %q", pair)
+		}
+		points = append(points, model.Location{Lat: lat, Lon: lon})
+	}
+	return points, nil
+}
+
+// acceptsGeoJSON проверяет, что клиент явно просит application/geo+json
+func acceptsGeoJSON(header string) bool {
+	for _, mediaRange := range strings.Split(header, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		if strings.TrimSpace(mediaType) != export.GeoJSONContentType {
+			continue
+		}
+		// q=0 означает, что формат неприемлем
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+const zoomError = "zoom must be between 0 and 20"
+
+func validZoom(zoom int) bool {
+	return zoom >= 0 && zoom <= service.MaxZoom
+}
+
+// zoomFromQuery читает необязательный параметр zoom; clustered = false, если его нет
+func zoomFromQuery(r *http.Request) (zoom int, clustered bool, err error) {
+	value := r.URL.Query().Get("zoom")
+	if value == "" {
+		return 0, false, nil
+	}
+	zoom, err = strconv.Atoi(value)
+	if err != nil || !validZoom(zoom) {
+		return 0, false, errors.New(zoomError)
+	}
+	return zoom, true, nil
+}
+
+// clusterPlaces заменяет близкие на карте масштаба zoom места их группами
+func clusterPlaces(r *http.Request, result *model.LocationResult, zoom int) {
+	result.Clusters, result.Places = service.ClusterPlaces(result.Places, zoom, service.LanguageFromContext(r.Context()))
+}
+
+// locationFromPath собирает локацию из переменных пути {lat},{lon} и параметра name
+func locationFromPath(r *http.Request) (model.Location, error) {
+	vars := mux.Vars(r)
+
+	lat, err := strconv.ParseFloat(vars["lat"], 64)
+	if err != nil {
+		return model.Location{}, err
+	}
+	lon, err := strconv.ParseFloat(vars["lon"], 64)
+	if err != nil {
+		return model.Location{}, err
+	}
+
+	return model.Location{
+		Name: r.URL.Query().Get("name"),
+		Lat:  lat,
+		Lon:  lon,
+	}, nil
+}
+
+// writeCacheableJSON отдаёт JSON с ETag по хешу содержимого и отвечает 304,
+// если клиент уже имеет актуальную версию
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, v any, maxAge int) {
+	writeCacheable(w, r, v, maxAge, "application/json")
+}
+
+// writeCacheable — то же для JSON-совместимых форматов с другим Content-Type
+func writeCacheable(w http.ResponseWriter, r *http.Request, v any, maxAge int, contentType string) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeCacheableBody(w, r, append(body, '\n'), maxAge, contentType)
+}
+
+// writeCacheableBody отдаёт уже сериализованное содержимое с ETag.
+// Ответ на запрос с ключом API кешируется только клиентом: общий кеш отдал бы его
+// другим клиентам в обход проверки ключа и квоты
+func writeCacheableBody(w http.ResponseWriter, r *http.Request, body []byte, maxAge int, contentType string) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	scope := "public"
+	if apiKeyFromRequest(r) != "" {
+		scope = "private"
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", scope+", max-age="+strconv.Itoa(maxAge))
+	w.Header().Set("Vary", "Accept, Accept-Language")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+// etagMatches реализует слабое сравнение из RFC 9110 для If-None-Match
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"places/internal/adapter/in/export"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+func TestWriteCacheableBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		cacheControl string
+	}{
+		{"anonymous", nil, "public, max-age=60"},
+		{"X-API-Key", map[string]string{"X-API-Key": "pk_alpha"}, "private, max-age=60"},
+		{"bearer", map[string]string{"Authorization": "Bearer pk_alpha"}, "private, max-age=60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search?q=x", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			writeCacheableBody(rec, req, []byte(`{"ok":true}`), 60, "application/json")
+
+			if rec.Code != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+
+			// Повторный запрос с тем же ETag получает 304 с теми же заголовками кеширования
+			req.Header.Set("If-None-Match", `W/`+rec.Header().Get("ETag"))
+			rec2 := httptest.NewRecorder()
+			writeCacheableBody(rec2, req, []byte(`{"ok":true}`), 60, "application/json")
+			if rec2.Code != http.StatusNotModified || rec2.Body.Len() != 0 {
+				t.Errorf("revalidation: status %d, body %q", rec2.Code, rec2.Body)
+			}
+			if got := rec2.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("revalidation Cache-Control = %q", got)
+			}
+		})
+	}
+}
+
+// detailsFunc отдаёт заданные места или ошибку провайдера
+type detailsFunc struct {
+	service.Service
+	places []model.Place
+	err    error
+}
+
+func (d detailsFunc) GetLocationDetails(_ context.Context, location model.Location) (*model.LocationResult, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &model.LocationResult{Location: location, Places: d.places}, nil
+}
+
+func TestGetLocationDetailsGeoJSON(t *testing.T) {
+	near := []model.Place{{Xid: "a", Lat: 55, Lon: 83}, {Xid: "b", Lat: 55.0001, Lon: 83.0001}}
+	tests := []struct {
+		name     string
+		lat, lon string
+		query    string
+		src      detailsFunc
+		status   int
+		features int
+	}{
+		{"no places", "55", "83", "", detailsFunc{}, http.StatusOK, 1},
+		{"places", "55", "83", "", detailsFunc{places: near}, http.StatusOK, 3},
+		{"clustered", "55", "83", "?zoom=10", detailsFunc{places: near}, http.StatusOK, 2},
+		{"boundary coordinates", "-90", "180", "", detailsFunc{}, http.StatusOK, 1},
+		{"invalid lat", "north", "83", "", detailsFunc{}, http.StatusBadRequest, 0},
+		{"zoom out of range", "55", "83", "?zoom=99", detailsFunc{}, http.StatusBadRequest, 0},
+		{"provider error", "55", "83", "", detailsFunc{err: errors.New("weather unavailable")}, http.StatusInternalServerError, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/locations/x/details.geojson"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"lat": tt.lat, "lon": tt.lon})
+			rec := httptest.NewRecorder()
+			NewHandler(tt.src).GetLocationDetailsGeoJSON(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != export.GeoJSONContentType {
+				t.Errorf("Content-Type = %q", got)
+			}
+			var collection export.FeatureCollection
+			if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil {
+				t.Fatal(err)
+			}
+			if len(collection.Features) != tt.features {
+				t.Errorf("features = %+v, want %d", collection.Features, tt.features)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package in
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"places/internal/service"
+)
+
+const (
+	defaultHistoryLimit = 50
+	defaultStatsWindow  = 7 * 24 * time.Hour
+	// Статистика считается перебором истории, поэтому период и размер ответа ограничены
+	maxStatsWindow    = 90 * 24 * time.Hour
+	defaultStatsLimit = 20
+	maxStatsLimit     = 100
+)
+
+type HistoryHandler struct {
+	src service.HistoryService
+}
+
+func NewHistoryHandler(service service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{
+		src: service,
+	}
+}
+
+// History отдаёт историю запросов пользователя: /api/history?limit=
+func (h *HistoryHandler) History(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := intParam(r, "limit", defaultHistoryLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.src.History(r.Context(), user, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, entries)
+}
+
+// Popular отдаёт популярные запросы и локации за период.
+// Период задаётся либо from/to в RFC 3339, либо длительностью window (например, 24h),
+// и не превышает maxStatsWindow
+func (h *HistoryHandler) Popular(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	to := time.Now().UTC()
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultStatsWindow)
+	if v := q.Get("window"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		from = to.Add(-window)
+	}
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if from.After(to) {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) > maxStatsWindow {
+		http.Error(w, fmt.Sprintf("period must not exceed %s", maxStatsWindow), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intParam(r, "limit", defaultStatsLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit <= 0 {
+		http.Error(w, "limit must be positive", http.StatusBadRequest)
+		return
+	}
+	limit = min(limit, maxStatsLimit)
+
+	stats, err := h.src.Popular(r.Context(), from, to, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, stats)
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,72 @@
+package in
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// popularFunc запоминает период и лимит, с которыми запрошена статистика
+type popularFunc struct {
+	service.HistoryService
+	from, to time.Time
+	limit    int
+}
+
+func (p *popularFunc) Popular(_ context.Context, from, to time.Time, limit int) (*model.PopularStats, error) {
+	p.from, p.to, p.limit = from, to, limit
+	return &model.PopularStats{From: from, To: to}, nil
+}
+
+func TestPopularBounds(t *testing.T) {
+	to := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		window    time.Duration
+		wantLimit int
+	}{
+		{"defaults", "", http.StatusOK, defaultStatsWindow, defaultStatsLimit},
+		{"window", "window=24h", http.StatusOK, 24 * time.Hour, defaultStatsLimit},
+		{"from", "from=2024-05-01T00:00:00Z", http.StatusOK, 9 * 24 * time.Hour, defaultStatsLimit},
+		{"empty period", "from=2024-05-10T00:00:00Z", http.StatusOK, 0, defaultStatsLimit},
+		{"max window", "window=2160h", http.StatusOK, maxStatsWindow, defaultStatsLimit},
+		{"limit capped", "limit=100000", http.StatusOK, defaultStatsWindow, maxStatsLimit},
+		{"window too long", "window=2161h", http.StatusBadRequest, 0, 0},
+		{"from too early", "from=2020-01-01T00:00:00Z", http.StatusBadRequest, 0, 0},
+		{"negative window", "window=-1h", http.StatusBadRequest, 0, 0},
+		{"from after to", "from=2024-05-11T00:00:00Z", http.StatusBadRequest, 0, 0},
+		{"zero limit", "limit=0", http.StatusBadRequest, 0, 0},
+		{"negative limit", "limit=-1", http.StatusBadRequest, 0, 0},
+		{"bad limit", "limit=many", http.StatusBadRequest, 0, 0},
+		{"bad to", "to=yesterday", http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &popularFunc{}
+			// Первое значение параметра важнее, поэтому to по умолчанию идёт последним
+			target := "/api/admin/stats/popular?" + tt.query + "&to=2024-05-10T00:00:00Z"
+			rec := httptest.NewRecorder()
+			NewHistoryHandler(src).Popular(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				if src.limit != 0 {
+					t.Error("invalid request reached the service")
+				}
+				return
+			}
+			if !src.to.Equal(to) || src.to.Sub(src.from) != tt.window || src.limit != tt.wantLimit {
+				t.Errorf("from %v, to %v, limit %d", src.from, src.to, src.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package in
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+	"places/internal/service"
+)
+
+// Language определяет язык ответа: параметр lang, иначе заголовок Accept-Language.
+// Провайдерам передаётся только базовый код языка: "ru-RU" -> "ru"
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := service.NormalizeLanguage(r.URL.Query().Get("lang"))
+		if lang == "" {
+			lang = acceptLanguage(r.Header.Get("Accept-Language"))
+		}
+		if lang != "" {
+			r = r.WithContext(service.WithLanguage(r.Context(), lang))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withBodyLanguage учитывает поле lang из тела POST-запроса, оно важнее заголовка
+func withBodyLanguage(r *http.Request, lang string) *http.Request {
+	if lang = service.NormalizeLanguage(lang); lang != "" {
+		return r.WithContext(service.WithLanguage(r.Context(), lang))
+	}
+	return r
+}
+
+func acceptLanguage(header string) string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return ""
+	}
+	for _, tag := range tags {
+		if lang := service.NormalizeLanguage(tag.String()); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
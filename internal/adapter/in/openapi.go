@@ -0,0 +1,326 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"places/internal/adapter/in/export"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// NewOpenAPISpec собирает спецификацию OpenAPI 3 для публичного HTTP API: поиска,
+// деталей, мест и выгрузок. Схемы генерируются из Go-структур, поэтому контракт
+// не расходится с кодом. Пользовательские и служебные маршруты в неё не входят
+// (см. specScope) и проверяются своими обработчиками
+func NewOpenAPISpec() (*openapi3.T, error) {
+	schemas := openapi3.Schemas{}
+	generator := openapi3gen.NewGenerator(
+		openapi3gen.SchemaCustomizer(customizeSchema),
+		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
+			ExportComponentSchemas: true,
+			ExportTopLevelSchema:   true,
+		}),
+		openapi3gen.CreateTypeNameGenerator(schemaName),
+	)
+
+	types := []any{
+		searchRequest{},
+		locationDetailsRequest{},
+		batchDetailsRequest{},
+		batchDetailsResponse{},
+		placesSearchRequest{},
+		categoryResponse{},
+		model.LocationResult{},
+		errorResponse{},
+	}
+	for _, t := range types {
+		if _, err := generator.NewSchemaRefForValue(t, schemas); err != nil {
+			return nil, err
+		}
+	}
+
+	detailsParams := func() openapi3.Parameters {
+		return openapi3.Parameters{
+			{Value: openapi3.NewPathParameter("lat").
+				WithSchema(openapi3.NewFloat64Schema().WithMin(-90).WithMax(90))},
+			{Value: openapi3.NewPathParameter("lon").
+				WithSchema(openapi3.NewFloat64Schema().WithMin(-180).WithMax(180))},
+			{Value: openapi3.NewQueryParameter("name").
+				WithSchema(openapi3.NewStringSchema())},
+			langParameter(),
+			{Value: openapi3.NewQueryParameter("units").
+				WithSchema(unitsSchema())},
+		}
+	}
+
+	// zoom нужен только картам: выгрузки для навигаторов его не принимают
+	mapParams := func() openapi3.Parameters {
+		return append(detailsParams(), zoomParameter())
+	}
+
+	details := cacheableOperation("getLocationDetailsByCoordinates", "Погода и интересные места для координат (кешируемый)",
+		mapParams(), schemaRef("LocationResult").Value)
+	// При Accept: application/geo+json ответ отдаётся как FeatureCollection
+	details.Responses.Status(http.StatusOK).Value.Content[export.GeoJSONContentType] =
+		openapi3.NewMediaType().WithSchema(featureCollectionSchema())
+
+	detailsGeoJSON := cacheableOperation("getLocationDetailsGeoJSON", "Погода и интересные места для координат в GeoJSON",
+		mapParams(), featureCollectionSchema())
+	detailsGeoJSON.Responses.Status(http.StatusOK).Value.Content = openapi3.NewContentWithSchema(
+		featureCollectionSchema(), []string{export.GeoJSONContentType})
+
+	exportParams := func() openapi3.Parameters {
+		return append(detailsParams(),
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("places").
+				WithDescription("xid выбранных мест через запятую").
+				WithSchema(openapi3.NewStringSchema())},
+			&openapi3.ParameterRef{Value: openapi3.NewQueryParameter("route").
+				WithDescription("Точки маршрута lat,lon через точку с запятой. Сервис маршрут не строит: " +
+					"точки клиента выгружаются как есть, треком в GPX и линией в KML").
+				WithSchema(openapi3.NewStringSchema())},
+		)
+	}
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Places API",
+			Version:     "1.0.0",
+			Description: specScope,
+		},
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/api/search", &openapi3.PathItem{
+				Post: jsonOperation("searchLocations", "Поиск локаций по названию, координатам или Plus Code",
+					"SearchRequest", openapi3.NewArraySchema().WithItems(schemaRef("Location").Value)),
+				Get: cacheableOperation("searchLocationsByQuery", "Поиск локаций по названию, координатам или Plus Code (кешируемый)",
+					openapi3.Parameters{
+						{Value: openapi3.NewQueryParameter("q").WithRequired(true).
+							WithSchema(openapi3.NewStringSchema().WithMinLength(1))},
+						langParameter(),
+					},
+					openapi3.NewArraySchema().WithItems(schemaRef("Location").Value)),
+			}),
+			openapi3.WithPath("/api/categories", &openapi3.PathItem{
+				Get: cacheableOperation("getCategories", "Таксономия категорий мест",
+					openapi3.Parameters{langParameter()},
+					openapi3.NewArraySchema().WithItems(schemaRef("CategoryResponse").Value)),
+			}),
+			openapi3.WithPath("/api/places", &openapi3.PathItem{
+				Get: cacheableOperation("getPlacesInViewport", "Места в видимой части карты",
+					openapi3.Parameters{
+						{Value: openapi3.NewQueryParameter("bbox").WithRequired(true).
+							WithDescription("Прямоугольник west,south,east,north, не больше 2° по каждой стороне").
+							WithSchema(openapi3.NewStringSchema())},
+						langParameter(),
+						{Value: openapi3.NewQueryParameter("units").
+							WithSchema(unitsSchema())},
+					},
+					placesInAreaSchema()),
+			}),
+			openapi3.WithPath("/api/places:search", &openapi3.PathItem{
+				Post: jsonOperation("searchPlacesInArea", "Места в прямоугольнике или полигоне GeoJSON",
+					"PlacesSearchRequest", placesInAreaSchema()),
+			}),
+			openapi3.WithPath("/api/autocomplete", &openapi3.PathItem{
+				Get: cacheableOperation("autocompleteLocations", "Подсказки локаций по мере ввода",
+					openapi3.Parameters{
+						{Value: openapi3.NewQueryParameter("q").WithRequired(true).
+							WithDescription("Начало названия; короче трёх символов подсказок нет").
+							WithSchema(openapi3.NewStringSchema().WithMinLength(1))},
+						{Value: openapi3.NewQueryParameter("session").
+							WithDescription("Случайный идентификатор поля ввода: новый запрос с той же сессией, " +
+								"ключом API и адресом клиента отменяет незавершённый предыдущий, тот получает 409").
+							WithSchema(openapi3.NewStringSchema())},
+						langParameter(),
+					},
+					openapi3.NewArraySchema().WithItems(schemaRef("Location").Value)),
+			}),
+			openapi3.WithPath("/api/location/details", &openapi3.PathItem{
+				Post: jsonOperation("getLocationDetails", "Погода и интересные места для локации",
+					"LocationDetailsRequest", schemaRef("LocationResult").Value),
+			}),
+			openapi3.WithPath("/api/locations/details:batch", &openapi3.PathItem{
+				Post: jsonOperation("getLocationDetailsBatch", "Погода и интересные места для нескольких локаций",
+					"BatchDetailsRequest", schemaRef("BatchDetailsResponse").Value),
+			}),
+			openapi3.WithPath("/api/locations/{lat},{lon}/details", &openapi3.PathItem{
+				Get: details,
+			}),
+			openapi3.WithPath("/api/locations/{lat},{lon}/details.geojson", &openapi3.PathItem{
+				Get: detailsGeoJSON,
+			}),
+			openapi3.WithPath("/api/locations/{lat},{lon}/details.gpx", &openapi3.PathItem{
+				Get: fileOperation("getLocationDetailsGPX", "Выгрузка локации и мест в GPX",
+					exportParams(), export.GPXContentType),
+			}),
+			openapi3.WithPath("/api/locations/{lat},{lon}/details.kml", &openapi3.PathItem{
+				Get: fileOperation("getLocationDetailsKML", "Выгрузка локации и мест в KML",
+					exportParams(), export.KMLContentType),
+			}),
+		),
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+
+	// Перечитываем документ загрузчиком, чтобы разрешить все $ref
+	data, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	spec, err = openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// specScope перечисляет маршруты вне спецификации: их запросы не проходят валидацию по ней
+const specScope = "Спецификация описывает поиск локаций, детали, места и выгрузки. " +
+	"Не описаны: избранное /api/favorites, история /api/history, " +
+	"административные /api/admin/keys и /api/admin/stats/popular, " +
+	"а также /graphql, схема которого отдаётся интроспекцией GraphQL"
+
+// OpenAPIHandler отдаёт спецификацию в формате JSON
+func OpenAPIHandler(spec *openapi3.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(spec); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// placesInAreaSchema — ответ поиска в области: провайдер отдаёт ограниченное число мест
+func placesInAreaSchema() *openapi3.Schema {
+	schema := openapi3.NewArraySchema().WithItems(schemaRef("Place").Value)
+	schema.Description = "Не больше 50 мест в порядке провайдера: в плотной застройке это не все места области. " +
+		"Полигон ищется по описанному прямоугольнику постранично, до 250 мест-кандидатов, " +
+		"поэтому в узком полигоне внутри большого прямоугольника мест может найтись меньше или ни одного"
+	return schema
+}
+
+func jsonOperation(id, summary, requestSchema string, response *openapi3.Schema) *openapi3.Operation {
+	op := openapi3.NewOperation()
+	op.OperationID = id
+	op.Summary = summary
+	op.RequestBody = &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schemaRef(requestSchema)),
+	}
+	op.AddResponse(http.StatusOK, openapi3.NewResponse().
+		WithDescription("OK").
+		WithJSONSchema(response))
+	op.AddResponse(http.StatusBadRequest, openapi3.NewResponse().
+		WithDescription("Запрос не прошёл валидацию").
+		WithJSONSchemaRef(schemaRef("ErrorResponse")))
+	return op
+}
+
+func cacheableOperation(id, summary string, params openapi3.Parameters, response *openapi3.Schema) *openapi3.Operation {
+	op := openapi3.NewOperation()
+	op.OperationID = id
+	op.Summary = summary
+	op.Parameters = append(params, &openapi3.ParameterRef{
+		Value: openapi3.NewHeaderParameter("If-None-Match").WithSchema(openapi3.NewStringSchema()),
+	})
+	op.AddResponse(http.StatusOK, openapi3.NewResponse().
+		WithDescription("OK").
+		WithJSONSchema(response))
+	op.AddResponse(http.StatusNotModified, openapi3.NewResponse().
+		WithDescription("Содержимое не изменилось с указанного ETag"))
+	op.AddResponse(http.StatusBadRequest, openapi3.NewResponse().
+		WithDescription("Запрос не прошёл валидацию").
+		WithJSONSchemaRef(schemaRef("ErrorResponse")))
+	return op
+}
+
+// fileOperation — кешируемая выгрузка в нетекстовом формате
+func fileOperation(id, summary string, params openapi3.Parameters, contentType string) *openapi3.Operation {
+	op := cacheableOperation(id, summary, params, openapi3.NewStringSchema())
+	op.Responses.Status(http.StatusOK).Value.Content = openapi3.NewContentWithSchema(
+		openapi3.NewStringSchema(), []string{contentType})
+	return op
+}
+
+// langParameter — язык ответа; без него используется Accept-Language
+func langParameter() *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{
+		Value: openapi3.NewQueryParameter("lang").WithSchema(openapi3.NewStringSchema()),
+	}
+}
+
+func zoomParameter() *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{Value: openapi3.NewQueryParameter("zoom").
+		WithDescription("Уровень масштаба карты: близкие места объединяются в группы").
+		WithSchema(zoomSchema())}
+}
+
+func zoomSchema() *openapi3.Schema {
+	return openapi3.NewIntegerSchema().WithMin(0).WithMax(service.MaxZoom)
+}
+
+func unitsSchema() *openapi3.Schema {
+	return openapi3.NewStringSchema().WithEnum(model.UnitsMetric, model.UnitsImperial, model.UnitsStandard)
+}
+
+// featureCollectionSchema описывает GeoJSON только на верхнем уровне,
+// свойства объектов зависят от их вида
+func featureCollectionSchema() *openapi3.Schema {
+	return openapi3.NewObjectSchema().
+		WithProperty("type", openapi3.NewStringSchema().WithEnum("FeatureCollection")).
+		WithProperty("features", openapi3.NewArraySchema().WithItems(openapi3.NewObjectSchema()))
+}
+
+func schemaRef(name string) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, &openapi3.Schema{})
+}
+
+// schemaName делает имена неэкспортируемых типов запросов пригодными для спецификации
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// customizeSchema дополняет сгенерированные схемы ограничениями:
+// поля без omitempty обязательны, координаты ограничены допустимым диапазоном
+func customizeSchema(name string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
+	switch name {
+	case "lat", "south", "north":
+		schema.WithMin(-90).WithMax(90)
+	case "lon", "west", "east":
+		schema.WithMin(-180).WithMax(180)
+	case "query":
+		schema.WithMinLength(1)
+	case "units":
+		schema.Enum = unitsSchema().Enum
+	case "zoom":
+		schema.WithMin(0).WithMax(service.MaxZoom)
+	case "locations":
+		schema.WithMinItems(1)
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("json")
+		if !ok || tag == "-" || strings.Contains(tag, "omitempty") {
+			continue
+		}
+		// Указатели и срезы могут прийти как null
+		if k := field.Type.Kind(); k == reflect.Pointer || k == reflect.Slice {
+			continue
+		}
+		schema.Required = append(schema.Required, strings.Split(tag, ",")[0])
+	}
+	return nil
+}
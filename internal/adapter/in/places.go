@@ -0,0 +1,76 @@
+package in
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// placesSearchRequest — поиск мест в области: ровно одно из bbox и polygon
+type placesSearchRequest struct {
+	BBox    *model.BBox    `json:"bbox,omitempty"`
+	Polygon *model.Polygon `json:"polygon,omitempty"`
+	Lang    string         `json:"lang,omitempty"`
+	Units   string         `json:"units,omitempty"`
+}
+
+// GetPlacesInViewport — места в видимой части карты: /api/places?bbox=west,south,east,north
+func (h *Handler) GetPlacesInViewport(w http.ResponseWriter, r *http.Request) {
+	bbox, err := parseBBox(r.URL.Query().Get("bbox"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	places, err := h.src.GetPlacesInArea(r.Context(), model.Area{BBox: &bbox})
+	if err != nil {
+		writeResult(w, nil, err)
+		return
+	}
+
+	writeCacheableJSON(w, r, places, detailsMaxAge)
+}
+
+// SearchPlacesInArea — места в прямоугольнике или полигоне GeoJSON: POST /api/places:search
+func (h *Handler) SearchPlacesInArea(w http.ResponseWriter, r *http.Request) {
+	var req placesSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r = withBodyLanguage(r, req.Lang)
+	if req.Units != "" {
+		if !service.ValidUnits(req.Units) {
+			http.Error(w, "units must be one of metric, imperial, standard", http.StatusBadRequest)
+			return
+		}
+		r = r.WithContext(service.WithUnits(r.Context(), req.Units))
+	}
+
+	places, err := h.src.GetPlacesInArea(r.Context(), model.Area{BBox: req.BBox, Polygon: req.Polygon})
+	writeResult(w, places, err)
+}
+
+// parseBBox разбирает прямоугольник в порядке west,south,east,north, как bbox в GeoJSON
+func parseBBox(value string) (model.BBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return model.BBox{}, errors.New("bbox must be west,south,east,north")
+	}
+
+	var coords [4]float64
+	for i, part := range parts {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return model.BBox{}, errors.New("bbox must be west,south,east,north")
+		}
+		coords[i] = coord
+	}
+	return model.BBox{West: coords[0], South: coords[1], East: coords[2], North: coords[3]}, nil
+}
@@ -0,0 +1,202 @@
+package in
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTTL — через сколько неактивный клиент забывается
+const limiterIdleTTL = 10 * time.Minute
+
+// ErrCostExceedsBurst — стоимость запроса больше burst: такой запрос не пройдёт никогда
+var ErrCostExceedsBurst = errors.New("request cost exceeds rate limit burst")
+
+// RateLimiter ограничивает частоту запросов с одного IP алгоритмом token bucket
+type RateLimiter struct {
+	rate           rate.Limit
+	burst          int
+	trustedProxies []*net.IPNet
+
+	mu      sync.Mutex
+	clients map[string]*clientLimiter
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter создает ограничитель: perSecond токенов в секунду, не больше burst подряд.
+// trustedProxies — подсети прокси, которым разрешено передавать X-Forwarded-For
+func NewRateLimiter(perSecond float64, burst int, trustedProxies []*net.IPNet) *RateLimiter {
+	rl := &RateLimiter{
+		rate:           rate.Limit(perSecond),
+		burst:          burst,
+		trustedProxies: trustedProxies,
+		clients:        make(map[string]*clientLimiter),
+	}
+	go rl.cleanup()
+	return rl
+}
+
+// Limit возвращает middleware, списывающий за запрос столько токенов,
+// сколько указано в costs для имени сработавшего маршрута. Маршруты без стоимости не ограничиваются
+func (rl *RateLimiter) Limit(costs map[string]int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cost, ok := costs[route.GetName()]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if rl.Allow(w, r, cost) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// Allow списывает cost токенов с клиента запроса. Если токенов не хватает,
+// отвечает 429 с Retry-After, а если cost больше burst — 413, и возвращает false.
+// Нужен обработчикам, чья стоимость известна только после разбора тела запроса
+func (rl *RateLimiter) Allow(w http.ResponseWriter, r *http.Request, cost int) bool {
+	retryAfter, err := rl.Take(rl.ClientIP(r), cost)
+	switch {
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return false
+	case retryAfter > 0:
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// Take списывает cost токенов с клиента ip. Если токенов не хватает, ничего
+// не списывает и возвращает, через сколько повторить запрос
+func (rl *RateLimiter) Take(ip string, cost int) (time.Duration, error) {
+	if cost > rl.burst {
+		return 0, fmt.Errorf("%w: cost %d, burst %d", ErrCostExceedsBurst, cost, rl.burst)
+	}
+	now := time.Now()
+	reservation := rl.limiter(ip, now).ReserveN(now, cost)
+	if !reservation.OK() {
+		return 0, ErrCostExceedsBurst
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Токены не тратим: клиент повторит запрос позже
+		reservation.CancelAt(now)
+		return delay, nil
+	}
+	return 0, nil
+}
+
+func (rl *RateLimiter) limiter(ip string, now time.Time) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	c, ok := rl.clients[ip]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(rl.rate, rl.burst)}
+		rl.clients[ip] = c
+	}
+	c.lastSeen = now
+	return c.limiter
+}
+
+// cleanup периодически удаляет давно неактивных клиентов, чтобы карта не росла бесконечно
+func (rl *RateLimiter) cleanup() {
+	ticker := time.NewTicker(limiterIdleTTL)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		rl.mu.Lock()
+		for ip, c := range rl.clients {
+			if now.Sub(c.lastSeen) > limiterIdleTTL {
+				delete(rl.clients, ip)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
+
+// ClientIP определяет адрес клиента. X-Forwarded-For учитывается, только если
+// запрос пришёл от доверенного прокси; список разбирается справа налево
+// до первого адреса, не принадлежащего доверенным прокси
+func (rl *RateLimiter) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !rl.trusted(net.ParseIP(host)) {
+		return host
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			break
+		}
+		if !rl.trusted(ip) {
+			return ip.String()
+		}
+		host = ip.String()
+	}
+	return host
+}
+
+func (rl *RateLimiter) trusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range rl.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCIDRs разбирает список подсетей через запятую; одиночные адреса допускаются
+func ParseCIDRs(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			if ip := net.ParseIP(item); ip != nil && ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
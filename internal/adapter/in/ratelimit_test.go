@@ -0,0 +1,148 @@
+package in
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAllow(t *testing.T) {
+	rl := NewRateLimiter(1, 10, nil)
+	request := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	rec := httptest.NewRecorder()
+	if !rl.Allow(rec, request("192.0.2.1:1000"), 10) {
+		t.Fatalf("first request rejected: %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	if rl.Allow(rec, request("192.0.2.1:1001"), 2) {
+		t.Fatal("request over the burst allowed")
+	}
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("status %d, Retry-After %q, want 429 and 2", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// Отклонённый запрос не тратит токены: ожидание не растёт. У другого клиента свой лимит
+	if retryAfter, _ := rl.Take("192.0.2.1", 2); retryAfter <= time.Second || retryAfter > 2*time.Second {
+		t.Errorf("retry after %v, want up to 2s", retryAfter)
+	}
+	rec = httptest.NewRecorder()
+	if !rl.Allow(rec, request("192.0.2.2:1000"), 10) {
+		t.Errorf("other client rejected: %d", rec.Code)
+	}
+
+	// Стоимость больше burst не пройдёт никогда: вместо Retry-After — 413
+	rec = httptest.NewRecorder()
+	if rl.Allow(rec, request("192.0.2.3:1000"), 11) {
+		t.Fatal("request costing more than the burst allowed")
+	}
+	if rec.Code != http.StatusRequestEntityTooLarge || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("status %d, Retry-After %q, want 413 without Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if _, err := rl.Take("192.0.2.3", 11); !errors.Is(err, ErrCostExceedsBurst) {
+		t.Errorf("err = %v, want ErrCostExceedsBurst", err)
+	}
+}
+
+func TestLimitRouteCosts(t *testing.T) {
+	rl := NewRateLimiter(0.001, 10, nil)
+	router := mux.NewRouter()
+	router.Use(rl.Limit(map[string]int{"search": 1, "details": 10}))
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/search", ok).Name("search")
+	router.HandleFunc("/details", ok).Name("details")
+	router.HandleFunc("/categories", ok).Name("categories")
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1000"
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Поиск стоит 1 токен: после него на детали (10) уже не хватает
+	if code := serve("/search"); code != http.StatusOK {
+		t.Fatalf("search: %d", code)
+	}
+	if code := serve("/details"); code != http.StatusTooManyRequests {
+		t.Errorf("details: %d, want 429", code)
+	}
+	for range 9 {
+		if code := serve("/search"); code != http.StatusOK {
+			t.Fatalf("search: %d", code)
+		}
+	}
+	if code := serve("/search"); code != http.StatusTooManyRequests {
+		t.Errorf("search after burst: %d, want 429", code)
+	}
+	// Маршруты без стоимости не ограничиваются
+	if code := serve("/categories"); code != http.StatusOK {
+		t.Errorf("categories: %d, want 200", code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseCIDRs("10.0.0.0/8, 192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := NewRateLimiter(1, 1, proxies)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{"direct client", "203.0.113.5:1000", "", "203.0.113.5"},
+		{"untrusted peer header is ignored", "203.0.113.5:1000", "198.51.100.1", "203.0.113.5"},
+		{"trusted proxy", "10.0.0.1:1000", "198.51.100.1", "198.51.100.1"},
+		{"single trusted address", "192.0.2.10:1000", "198.51.100.1", "198.51.100.1"},
+		{"spoofed leftmost hop", "10.0.0.1:1000", "1.1.1.1, 198.51.100.1, 10.0.0.2", "198.51.100.1"},
+		{"only proxies", "10.0.0.1:1000", "10.0.0.3, 10.0.0.2", "10.0.0.3"},
+		{"garbage stops the walk", "10.0.0.1:1000", "198.51.100.1, junk", "10.0.0.1"},
+		{"no header from proxy", "10.0.0.1:1000", "", "10.0.0.1"},
+		{"remote addr without port", "203.0.113.5", "", "203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if got := rl.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	networks, err := ParseCIDRs(" 10.0.0.0/8 ,, 192.0.2.1, ::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8", "192.0.2.1/32", "::1/128"}
+	if len(networks) != len(want) {
+		t.Fatalf("networks = %v", networks)
+	}
+	for i, network := range networks {
+		if network.String() != want[i] {
+			t.Errorf("network %d = %s, want %s", i, network, want[i])
+		}
+	}
+	if _, err := ParseCIDRs("10.0.0.0/33"); err == nil {
+		t.Error("invalid CIDR: expected error")
+	}
+}
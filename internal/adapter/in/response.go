@@ -0,0 +1,41 @@
+package in
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"places/internal/model"
+)
+
+// writeResult переводит доменные ошибки в HTTP-статусы
+func writeResult(w http.ResponseWriter, v any, err error) {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, model.ErrAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, model.ErrInvalidArea):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, v)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	writeJSONStatus(w, http.StatusOK, v)
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// RequireAPIKey возвращает перехватчики, пропускающие только вызовы с действующим ключом
+// в метаданных x-api-key или authorization: Bearer и неисчерпанной квотой. Как и в HTTP,
+// в контекст кладутся ключ и привязанный к нему пользователь из x-user-id
+func RequireAPIKey(keys service.APIKeyService) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		key, err := keys.Authenticate(ctx, apiKeyFromMetadata(md))
+		switch {
+		case errors.Is(err, model.ErrQuotaExceeded):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, model.ErrUnauthorized):
+			return nil, status.Error(codes.Unauthenticated, "valid API key is required")
+		case err != nil:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		ctx = service.WithAPIKeyID(ctx, key.ID)
+		return service.WithUserID(ctx, service.KeyUserID(key.ID, firstValue(md, "x-user-id"))), nil
+	}
+
+	unary := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+	return unary, stream
+}
+
+// serverStream подменяет контекст потока контекстом с ключом и пользователем
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func apiKeyFromMetadata(md metadata.MD) string {
+	if key := firstValue(md, "x-api-key"); key != "" {
+		return key
+	}
+	if auth := firstValue(md, "authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
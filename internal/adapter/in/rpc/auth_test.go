@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// authFunc принимает единственный ключ pk_alpha; ключ "exhausted" исчерпал квоту
+type authFunc struct {
+	service.APIKeyService
+}
+
+func (authFunc) Authenticate(_ context.Context, token string) (*model.APIKey, error) {
+	switch token {
+	case "pk_alpha":
+		return &model.APIKey{ID: "k1"}, nil
+	case "exhausted":
+		return nil, model.ErrQuotaExceeded
+	}
+	return nil, model.ErrUnauthorized
+}
+
+// contextStream — серверный поток, у которого есть только контекст
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context { return s.ctx }
+
+func TestRequireAPIKey(t *testing.T) {
+	unary, stream := RequireAPIKey(authFunc{})
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+		user string
+	}{
+		{"no key", metadata.MD{}, codes.Unauthenticated, ""},
+		{"unknown key", metadata.Pairs("x-api-key", "pk_beta"), codes.Unauthenticated, ""},
+		{"x-api-key", metadata.Pairs("x-api-key", "pk_alpha"), codes.OK, "k1"},
+		{"bearer", metadata.Pairs("authorization", "Bearer pk_alpha", "x-user-id", "alice"), codes.OK, "k1/alice"},
+		{"quota exceeded", metadata.Pairs("x-api-key", "exhausted"), codes.ResourceExhausted, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var unaryUser string
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				unaryUser = service.UserIDFromContext(ctx)
+				if service.APIKeyIDFromContext(ctx) != "k1" {
+					t.Error("key id is not in the context")
+				}
+				return nil, nil
+			})
+			if status.Code(err) != tt.code || unaryUser != tt.user {
+				t.Errorf("unary: code %v, user %q, want %v, %q", status.Code(err), unaryUser, tt.code, tt.user)
+			}
+
+			var streamUser string
+			err = stream(nil, contextStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+				streamUser = service.UserIDFromContext(ss.Context())
+				return nil
+			})
+			if status.Code(err) != tt.code || streamUser != tt.user {
+				t.Errorf("stream: code %v, user %q, want %v, %q", status.Code(err), streamUser, tt.code, tt.user)
+			}
+		})
+	}
+}
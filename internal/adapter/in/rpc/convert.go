@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	placesv1 "places/api/places/v1"
+	"places/internal/model"
+)
+
+func toProtoLocation(l model.Location) *placesv1.Location {
+	return &placesv1.Location{
+		Name:    l.Name,
+		Lat:     l.Lat,
+		Lon:     l.Lon,
+		Country: l.Country,
+		State:   l.State,
+	}
+}
+
+func fromProtoLocation(l *placesv1.Location) model.Location {
+	return model.Location{
+		Name:    l.GetName(),
+		Lat:     l.GetLat(),
+		Lon:     l.GetLon(),
+		Country: l.GetCountry(),
+		State:   l.GetState(),
+	}
+}
+
+func toProtoWeather(w *model.Weather) *placesv1.Weather {
+	if w == nil {
+		return nil
+	}
+	return &placesv1.Weather{
+		Temp:        w.Temp,
+		FeelsLike:   w.FeelsLike,
+		Description: w.Description,
+		Humidity:    int32(w.Humidity),
+		WindSpeed:   w.WindSpeed,
+		Icon:        w.Icon,
+		Units:       w.Units,
+	}
+}
+
+func toProtoPlace(p model.Place) *placesv1.Place {
+	return &placesv1.Place{
+		Xid:             p.Xid,
+		Name:            p.Name,
+		Kinds:           p.Kinds,
+		Lat:             p.Lat,
+		Lon:             p.Lon,
+		Distance:        p.Distance,
+		Description:     p.Description,
+		Image:           p.Image,
+		Website:         p.WebSite,
+		Wikipedia:       p.Wikipedia,
+		Units:           p.Units,
+		Score:           p.Score,
+		Reasons:         p.Reasons,
+		OpeningHours:    p.OpeningHours,
+		Categories:      toProtoCategories(p.Categories),
+		PrimaryCategory: toProtoCategory(p.PrimaryCategory),
+	}
+}
+
+func toProtoLocationResult(r *model.LocationResult) *placesv1.LocationResult {
+	result := &placesv1.LocationResult{
+		Location: toProtoLocation(r.Location),
+		Weather:  toProtoWeather(r.Weather),
+		Places:   make([]*placesv1.Place, 0, len(r.Places)),
+	}
+	for _, p := range r.Places {
+		result.Places = append(result.Places, toProtoPlace(p))
+	}
+	return result
+}
+
+func toProtoLocationEvent(e model.LocationEvent) *placesv1.LocationEvent {
+	if e.Weather != nil {
+		return &placesv1.LocationEvent{
+			Event: &placesv1.LocationEvent_Weather{Weather: toProtoWeather(e.Weather)},
+		}
+	}
+	return &placesv1.LocationEvent{
+		Event: &placesv1.LocationEvent_Place{Place: toProtoPlace(*e.Place)},
+	}
+}
+
+func toProtoCategories(refs []model.CategoryRef) []*placesv1.CategoryRef {
+	categories := make([]*placesv1.CategoryRef, len(refs))
+	for i := range refs {
+		categories[i] = toProtoCategory(&refs[i])
+	}
+	return categories
+}
+
+func toProtoCategory(ref *model.CategoryRef) *placesv1.CategoryRef {
+	if ref == nil {
+		return nil
+	}
+	return &placesv1.CategoryRef{Id: ref.ID, Label: ref.Label, Icon: ref.Icon}
+}
@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Limiter списывает стоимость вызова с клиента по его адресу.
+// Если токенов не хватает, возвращает, через сколько повторить вызов
+type Limiter interface {
+	Take(ip string, cost int) (time.Duration, error)
+}
+
+// RateLimit возвращает перехватчики, списывающие за вызов столько токенов,
+// сколько указано в costs для полного имени метода. Методы без стоимости не ограничиваются.
+// Клиент определяется по адресу соединения: прокси перед gRPC не учитываются
+func RateLimit(limiter Limiter, costs map[string]int) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	// take возвращает ошибку и заголовок retry-after, если вызов не укладывается в лимит
+	take := func(ctx context.Context, method string) (metadata.MD, error) {
+		cost, ok := costs[method]
+		if !ok {
+			return nil, nil
+		}
+		retryAfter, err := limiter.Take(peerIP(ctx), cost)
+		if err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if retryAfter > 0 {
+			seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+			return metadata.Pairs("retry-after", seconds),
+				status.Error(codes.ResourceExhausted, fmt.Sprintf("too many requests, retry after %ss", seconds))
+		}
+		return nil, nil
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if header, err := take(ctx, info.FullMethod); err != nil {
+			_ = grpc.SetHeader(ctx, header)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if header, err := take(ss.Context(), info.FullMethod); err != nil {
+			_ = ss.SetHeader(header)
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+// peerIP возвращает адрес клиента без порта
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// limiterFunc отклоняет вызовы дороже budget и запоминает адреса клиентов
+type limiterFunc struct {
+	budget int
+	ips    []string
+}
+
+func (l *limiterFunc) Take(ip string, cost int) (time.Duration, error) {
+	l.ips = append(l.ips, ip)
+	if cost > 100 {
+		return 0, errors.New("request cost exceeds rate limit burst")
+	}
+	if cost > l.budget {
+		return 1500 * time.Millisecond, nil
+	}
+	l.budget -= cost
+	return 0, nil
+}
+
+func TestRateLimitUnary(t *testing.T) {
+	limiter := &limiterFunc{budget: 10}
+	unary, _ := RateLimit(limiter, map[string]int{"/search": 1, "/details": 10, "/huge": 200})
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 5000}})
+
+	call := func(method string) error {
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			return "ok", nil
+		})
+		return err
+	}
+
+	if err := call("/details"); err != nil {
+		t.Fatal(err)
+	}
+	if err := call("/search"); status.Code(err) != codes.ResourceExhausted || status.Convert(err).Message() != "too many requests, retry after 2s" {
+		t.Errorf("err = %v, want ResourceExhausted with retry", err)
+	}
+	if err := call("/huge"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("err = %v, want ResourceExhausted", err)
+	}
+	// Методы без стоимости не ограничиваются
+	if err := call("/other"); err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if len(limiter.ips) != 3 || limiter.ips[0] != "192.0.2.1" {
+		t.Errorf("ips = %v", limiter.ips)
+	}
+}
@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	placesv1 "places/api/places/v1"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+type Server struct {
+	placesv1.UnimplementedPlacesServiceServer
+	src service.Service
+}
+
+func NewServer(service service.Service) *Server {
+	return &Server{
+		src: service,
+	}
+}
+
+func (s *Server) SearchLocations(ctx context.Context, req *placesv1.SearchLocationsRequest) (*placesv1.SearchLocationsResponse, error) {
+	if req.GetQuery() == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	locations, err := s.src.SearchLocations(withLanguage(ctx, req.GetLang()), req.GetQuery())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &placesv1.SearchLocationsResponse{
+		Locations: make([]*placesv1.Location, 0, len(locations)),
+	}
+	for _, l := range locations {
+		resp.Locations = append(resp.Locations, toProtoLocation(l))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetLocationDetails(ctx context.Context, req *placesv1.GetLocationDetailsRequest) (*placesv1.LocationResult, error) {
+	ctx, err := withOptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.src.GetLocationDetails(ctx, fromProtoLocation(req.GetLocation()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProtoLocationResult(result), nil
+}
+
+func (s *Server) StreamLocationDetails(req *placesv1.GetLocationDetailsRequest, stream placesv1.PlacesService_StreamLocationDetailsServer) error {
+	// Отменяем контекст при ошибке отправки, чтобы сервис остановил запросы к провайдерам
+	ctx, err := withOptions(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for event := range s.src.StreamLocationDetails(ctx, fromProtoLocation(req.GetLocation())) {
+		if err := stream.Send(toProtoLocationEvent(event)); err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+// withOptions проверяет локацию запроса деталей и переносит язык и систему единиц в контекст
+func withOptions(ctx context.Context, req *placesv1.GetLocationDetailsRequest) (context.Context, error) {
+	location := req.GetLocation()
+	if location == nil {
+		return nil, status.Error(codes.InvalidArgument, "location is required")
+	}
+	// Сравнения ложны и для NaN, поэтому он тоже отклоняется
+	if !(location.GetLat() >= -90 && location.GetLat() <= 90) || !(location.GetLon() >= -180 && location.GetLon() <= 180) {
+		return nil, status.Error(codes.InvalidArgument, "lat must be within [-90, 90] and lon within [-180, 180]")
+	}
+
+	ctx = withLanguage(ctx, req.GetLang())
+	if units := req.GetUnits(); units != "" {
+		if !service.ValidUnits(units) {
+			return nil, status.Error(codes.InvalidArgument, "units must be one of metric, imperial, standard")
+		}
+		ctx = service.WithUnits(ctx, units)
+	}
+	return ctx, nil
+}
+
+// toStatus переводит ошибку сервиса в статус gRPC, как writeResult — в код HTTP
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// withLanguage передаёт сервису базовый код языка из запроса: "ru-RU" -> "ru"
+func withLanguage(ctx context.Context, lang string) context.Context {
+	if lang = service.NormalizeLanguage(lang); lang != "" {
+		return service.WithLanguage(ctx, lang)
+	}
+	return ctx
+}
@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	placesv1 "places/api/places/v1"
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// serviceFunc отдаёт заданные локации, события и ошибку и запоминает переданные единицы
+type serviceFunc struct {
+	service.Service
+	locations []model.Location
+	events    []model.LocationEvent
+	err       error
+	units     string
+}
+
+func (s *serviceFunc) SearchLocations(context.Context, string) ([]model.Location, error) {
+	return s.locations, s.err
+}
+
+func (s *serviceFunc) GetLocationDetails(ctx context.Context, location model.Location) (*model.LocationResult, error) {
+	s.units = service.UnitsFromContext(ctx)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.LocationResult{Location: location}, nil
+}
+
+func (s *serviceFunc) StreamLocationDetails(ctx context.Context, _ model.Location) <-chan model.LocationEvent {
+	events := make(chan model.LocationEvent)
+	go func() {
+		defer close(events)
+		for _, e := range s.events {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// sendStream собирает отправленные события; после failAfter отправок возвращает ошибку
+type sendStream struct {
+	contextStream
+	sent      []*placesv1.LocationEvent
+	failAfter int
+}
+
+func (s *sendStream) Send(e *placesv1.LocationEvent) error {
+	if s.failAfter > 0 && len(s.sent) == s.failAfter {
+		return errors.New("connection reset")
+	}
+	s.sent = append(s.sent, e)
+	return nil
+}
+
+func TestSearchLocations(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		src   *serviceFunc
+		code  codes.Code
+		want  int
+	}{
+		{"empty query", "", &serviceFunc{}, codes.InvalidArgument, 0},
+		{"no results", "Атлантида", &serviceFunc{}, codes.OK, 0},
+		{"results", "Новосибирск", &serviceFunc{locations: []model.Location{{Name: "Новосибирск", Lat: 55, Lon: 83}}}, codes.OK, 1},
+		{"provider error", "Новосибирск", &serviceFunc{err: errors.New("geocoder unavailable")}, codes.Internal, 0},
+		{"not found", "Новосибирск", &serviceFunc{err: fmt.Errorf("search: %w", model.ErrNotFound)}, codes.NotFound, 0},
+		{"deadline", "Новосибирск", &serviceFunc{err: context.DeadlineExceeded}, codes.DeadlineExceeded, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewServer(tt.src).SearchLocations(context.Background(), &placesv1.SearchLocationsRequest{Query: tt.query})
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.code, err)
+			}
+			if err == nil && len(resp.GetLocations()) != tt.want {
+				t.Errorf("locations = %v, want %d", resp.GetLocations(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLocationDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		location *placesv1.Location
+		units    string
+		err      error
+		code     codes.Code
+	}{
+		{"no location", nil, "", nil, codes.InvalidArgument},
+		{"zero location", &placesv1.Location{}, "", nil, codes.OK},
+		{"poles and antimeridian", &placesv1.Location{Lat: -90, Lon: 180}, "", nil, codes.OK},
+		{"lat above 90", &placesv1.Location{Lat: 90.0001, Lon: 0}, "", nil, codes.InvalidArgument},
+		{"lon below -180", &placesv1.Location{Lat: 0, Lon: -180.0001}, "", nil, codes.InvalidArgument},
+		{"NaN", &placesv1.Location{Lat: math.NaN(), Lon: 0}, "", nil, codes.InvalidArgument},
+		{"imperial units", &placesv1.Location{Lat: 55, Lon: 83}, model.UnitsImperial, nil, codes.OK},
+		{"unknown units", &placesv1.Location{Lat: 55, Lon: 83}, "kelvin", nil, codes.InvalidArgument},
+		{"provider error", &placesv1.Location{Lat: 55, Lon: 83}, "", errors.New("weather unavailable"), codes.Internal},
+		{"canceled", &placesv1.Location{Lat: 55, Lon: 83}, "", context.Canceled, codes.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &serviceFunc{err: tt.err}
+			resp, err := NewServer(src).GetLocationDetails(context.Background(),
+				&placesv1.GetLocationDetailsRequest{Location: tt.location, Units: tt.units})
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.code, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.GetLocation().GetLat() != tt.location.GetLat() || resp.GetLocation().GetLon() != tt.location.GetLon() {
+				t.Errorf("location = %v, want %v", resp.GetLocation(), tt.location)
+			}
+			if tt.units != "" && src.units != tt.units {
+				t.Errorf("units = %q, want %q", src.units, tt.units)
+			}
+		})
+	}
+}
+
+func TestStreamLocationDetails(t *testing.T) {
+	events := []model.LocationEvent{
+		{Weather: &model.Weather{Temp: 8}},
+		{Place: &model.Place{Xid: "N1"}},
+		{Place: &model.Place{Xid: "N2"}},
+	}
+	tests := []struct {
+		name      string
+		location  *placesv1.Location
+		events    []model.LocationEvent
+		failAfter int
+		code      codes.Code
+		wantSent  int
+	}{
+		{"no location", nil, events, 0, codes.InvalidArgument, 0},
+		{"out of range", &placesv1.Location{Lat: 91}, events, 0, codes.InvalidArgument, 0},
+		{"no events", &placesv1.Location{Lat: 55, Lon: 83}, nil, 0, codes.OK, 0},
+		{"all events", &placesv1.Location{Lat: 55, Lon: 83}, events, 0, codes.OK, 3},
+		{"send error", &placesv1.Location{Lat: 55, Lon: 83}, events, 1, codes.Unknown, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &sendStream{contextStream: contextStream{ctx: context.Background()}, failAfter: tt.failAfter}
+			err := NewServer(&serviceFunc{events: tt.events}).StreamLocationDetails(&placesv1.GetLocationDetailsRequest{Location: tt.location}, stream)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.code, err)
+			}
+			if len(stream.sent) != tt.wantSent {
+				t.Errorf("sent %d events, want %d", len(stream.sent), tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package in
+
+import (
+	"net/http"
+
+	"places/internal/service"
+)
+
+// Units переносит параметр units (metric, imperial, standard) в контекст запроса
+func Units(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		units := r.URL.Query().Get("units")
+		if units != "" {
+			if !service.ValidUnits(units) {
+				http.Error(w, "units must be one of metric, imperial, standard", http.StatusBadRequest)
+				return
+			}
+			r = r.WithContext(service.WithUnits(r.Context(), units))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
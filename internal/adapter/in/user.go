@@ -0,0 +1,34 @@
+package in
+
+import (
+	"net/http"
+
+	"places/internal/service"
+)
+
+// userHeader идентифицирует пользователя, которому принадлежат сохранённые данные
+const userHeader = "X-User-ID"
+
+// UserContext переносит идентификатор пользователя в контекст запроса, чтобы сервис
+// мог учитывать его, например, при записи истории. Пользователь привязывается к ключу API:
+// X-User-ID различает пользователей одного ключа, а без ключа запрос анонимный.
+// Поэтому middleware ставится после проверки ключа
+func UserContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := service.KeyUserID(service.APIKeyIDFromContext(r.Context()), r.Header.Get(userHeader))
+		if user != "" {
+			r = r.WithContext(service.WithUserID(r.Context(), user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireUser возвращает пользователя запроса; данные анонимного клиента не хранятся
+func requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := service.UserIDFromContext(r.Context())
+	if user == "" {
+		unauthorized(w, "API key is required to store user data")
+		return "", false
+	}
+	return user, true
+}
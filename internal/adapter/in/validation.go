@@ -0,0 +1,117 @@
+package in
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+// errorResponse структурированный ответ об ошибке валидации
+type errorResponse struct {
+	Error   string            `json:"error"`
+	Details []validationIssue `json:"details,omitempty"`
+}
+
+type validationIssue struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidateRequests проверяет входящие запросы по спецификации OpenAPI.
+// Запросы к путям, которых нет в спецификации, пропускаются без проверки
+func ValidateRequests(spec *openapi3.T) (mux.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				writeValidationError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	resp := errorResponse{Error: "request validation failed"}
+
+	var multi openapi3.MultiError
+	if !errors.As(err, &multi) {
+		multi = openapi3.MultiError{err}
+	}
+	for _, e := range multi {
+		resp.Details = append(resp.Details, toValidationIssues(e)...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// toValidationIssues разворачивает ошибки kin-openapi до отдельных полей
+func toValidationIssues(err error) []validationIssue {
+	// Список разворачивается только на верхнем уровне: errors.As нашёл бы его
+	// внутри ошибки параметра, а SchemaError — под RequestError, и имя параметра потерялось бы
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var issues []validationIssue
+		for _, e := range multi {
+			issues = append(issues, toValidationIssues(e)...)
+		}
+		return issues
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		issues := []validationIssue{{Message: reqErr.Reason}}
+		if reqErr.Err != nil && reqErr.Err != err {
+			issues = toValidationIssues(reqErr.Err)
+		}
+		// Для ошибок параметров пути и query указываем имя параметра
+		if reqErr.Parameter != nil {
+			for i := range issues {
+				if issues[i].Field == "" {
+					issues[i].Field = reqErr.Parameter.Name
+				}
+			}
+		}
+		return issues
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []validationIssue{{
+			Field:   strings.Join(schemaErr.JSONPointer(), "."),
+			Message: schemaErr.Reason,
+		}}
+	}
+
+	return []validationIssue{{Message: err.Error()}}
+}
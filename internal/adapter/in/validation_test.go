@@ -0,0 +1,83 @@
+package in
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateRequests(t *testing.T) {
+	spec, err := NewOpenAPISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate, err := ValidateRequests(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reached bool
+	handler := validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		status    int
+		wantField string
+	}{
+		{"valid query", http.MethodGet, "/api/search?q=Москва", "", http.StatusOK, ""},
+		{"valid body", http.MethodPost, "/api/search", `{"query":"Москва"}`, http.StatusOK, ""},
+		{"valid coordinates", http.MethodGet, "/api/locations/90,-180/details?units=imperial", "", http.StatusOK, ""},
+		{"missing query parameter", http.MethodGet, "/api/search", "", http.StatusBadRequest, "q"},
+		{"empty query parameter", http.MethodGet, "/api/search?q=", "", http.StatusBadRequest, "q"},
+		{"empty body field", http.MethodPost, "/api/search", `{"query":""}`, http.StatusBadRequest, "query"},
+		{"latitude out of range", http.MethodGet, "/api/locations/90.5,37/details", "", http.StatusBadRequest, "lat"},
+		{"unknown units", http.MethodGet, "/api/locations/55,37/details?units=kelvin", "", http.StatusBadRequest, "units"},
+		{"zoom out of range", http.MethodGet, "/api/locations/55,37/details?zoom=99", "", http.StatusBadRequest, "zoom"},
+		{"empty batch", http.MethodPost, "/api/locations/details:batch", `{"locations":[]}`, http.StatusBadRequest, "locations"},
+		// Маршруты вне спецификации проверяют свои обработчики
+		{"route outside spec", http.MethodPost, "/api/favorites/trip/places", `{"xid":""}`, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached = false
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if reached != (tt.status == http.StatusOK) {
+				t.Errorf("handler reached = %v", reached)
+			}
+			if tt.status == http.StatusOK {
+				return
+			}
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Header().Get("Content-Type") != "application/json" || resp.Error != "request validation failed" {
+				t.Errorf("response = %+v", resp)
+			}
+			fields := make([]string, 0, len(resp.Details))
+			for _, issue := range resp.Details {
+				fields = append(fields, issue.Field)
+			}
+			if !strings.Contains(strings.Join(fields, " "), tt.wantField) {
+				t.Errorf("fields = %v, want %q: %+v", fields, tt.wantField, resp)
+			}
+		})
+	}
+}
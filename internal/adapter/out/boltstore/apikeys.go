@@ -0,0 +1,230 @@
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"places/internal/model"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	apiKeysBucket      = []byte("api_keys")
+	apiKeyHashesBucket = []byte("api_key_hashes")
+	apiKeyUsageBucket  = []byte("api_key_usage")
+)
+
+// APIKeyStore хранит ключи API (id -> JSON), индекс хеш -> id
+// и дневные счётчики использования (bucket ключа -> день -> JSON)
+type APIKeyStore struct {
+	db *bolt.DB
+}
+
+func NewAPIKeyStore(db *bolt.DB) (*APIKeyStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{apiKeysBucket, apiKeyHashesBucket, apiKeyUsageBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyStore{db: db}, nil
+}
+
+func (s *APIKeyStore) CreateKey(_ context.Context, key model.APIKey, hash string) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(apiKeysBucket)
+		if keys.Get([]byte(key.ID)) != nil {
+			return fmt.Errorf("api key %s: %w", key.ID, model.ErrAlreadyExists)
+		}
+		if err := keys.Put([]byte(key.ID), data); err != nil {
+			return err
+		}
+		return tx.Bucket(apiKeyHashesBucket).Put([]byte(hash), []byte(key.ID))
+	})
+}
+
+func (s *APIKeyStore) KeyByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	var key *model.APIKey
+	err := s.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(apiKeyHashesBucket).Get([]byte(hash))
+		if id == nil {
+			return fmt.Errorf("api key: %w", model.ErrNotFound)
+		}
+		var err error
+		key, err = getKey(tx, string(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyStore) Keys(_ context.Context) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(apiKeysBucket).ForEach(func(_, v []byte) error {
+			var key model.APIKey
+			if err := json.Unmarshal(v, &key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (s *APIKeyStore) UpdateKey(_ context.Context, id string, fn func(key *model.APIKey) error) (*model.APIKey, error) {
+	var key *model.APIKey
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		key, err = getKey(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+		data, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(apiKeysBucket).Put([]byte(id), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyStore) DeleteKey(_ context.Context, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := getKey(tx, id); err != nil {
+			return err
+		}
+
+		// Удаляем запись из индекса хешей
+		hashes := tx.Bucket(apiKeyHashesBucket)
+		c := hashes.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if bytes.Equal(v, []byte(id)) {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if usage := tx.Bucket(apiKeyUsageBucket); usage.Bucket([]byte(id)) != nil {
+			if err := usage.DeleteBucket([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(apiKeysBucket).Delete([]byte(id))
+	})
+}
+
+func (s *APIKeyStore) ConsumeRequest(_ context.Context, id, day string, limit int) (*model.KeyUsage, error) {
+	var usage *model.KeyUsage
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		usage, err = updateUsage(tx, id, day, func(u *model.KeyUsage) error {
+			if limit > 0 && u.Requests >= limit {
+				return fmt.Errorf("api key %s: %d requests per day: %w", id, limit, model.ErrQuotaExceeded)
+			}
+			u.Requests++
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (s *APIKeyStore) AddProviderCalls(_ context.Context, id, day string, calls map[string]int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		_, err := updateUsage(tx, id, day, func(u *model.KeyUsage) error {
+			for provider, n := range calls {
+				u.ProviderCalls[provider] += n
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+func (s *APIKeyStore) Usage(_ context.Context, id, fromDay, toDay string) ([]model.KeyUsage, error) {
+	days := make([]model.KeyUsage, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(apiKeyUsageBucket).Bucket([]byte(id))
+		if bucket == nil {
+			return nil
+		}
+
+		// Дни в формате YYYY-MM-DD сортируются лексикографически
+		c := bucket.Cursor()
+		for k, v := c.Seek([]byte(fromDay)); k != nil && string(k) <= toDay; k, v = c.Next() {
+			var usage model.KeyUsage
+			if err := json.Unmarshal(v, &usage); err != nil {
+				return err
+			}
+			days = append(days, usage)
+		}
+		return nil
+	})
+	return days, err
+}
+
+func getKey(tx *bolt.Tx, id string) (*model.APIKey, error) {
+	data := tx.Bucket(apiKeysBucket).Get([]byte(id))
+	if data == nil {
+		return nil, fmt.Errorf("api key %s: %w", id, model.ErrNotFound)
+	}
+
+	var key model.APIKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func updateUsage(tx *bolt.Tx, id, day string, fn func(u *model.KeyUsage) error) (*model.KeyUsage, error) {
+	bucket, err := tx.Bucket(apiKeyUsageBucket).CreateBucketIfNotExists([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &model.KeyUsage{Day: day}
+	if data := bucket.Get([]byte(day)); data != nil {
+		if err := json.Unmarshal(data, usage); err != nil {
+			return nil, err
+		}
+	}
+	if usage.ProviderCalls == nil {
+		usage.ProviderCalls = make(map[string]int)
+	}
+
+	if err := fn(usage); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return nil, err
+	}
+	return usage, bucket.Put([]byte(day), data)
+}
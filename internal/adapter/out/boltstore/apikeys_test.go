@@ -0,0 +1,120 @@
+package boltstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+func TestAPIKeyStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewAPIKeyStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := model.APIKey{ID: "k1", Name: "mobile", Prefix: "pk_abcd", DailyQuota: 100, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := store.CreateKey(ctx, key, "hash1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateKey(ctx, key, "hash2"); !errors.Is(err, model.ErrAlreadyExists) {
+		t.Errorf("duplicate id err = %v, want ErrAlreadyExists", err)
+	}
+	if err := store.CreateKey(ctx, model.APIKey{ID: "k2", Name: "web"}, "hash3"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.KeyByHash(ctx, "hash1")
+	if err != nil || *got != key {
+		t.Fatalf("KeyByHash = %+v, %v", got, err)
+	}
+	if _, err := store.KeyByHash(ctx, "unknown"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("unknown hash err = %v, want ErrNotFound", err)
+	}
+
+	updated, err := store.UpdateKey(ctx, "k1", func(k *model.APIKey) error {
+		k.Disabled = true
+		return nil
+	})
+	if err != nil || !updated.Disabled {
+		t.Fatalf("UpdateKey = %+v, %v", updated, err)
+	}
+	if got, _ := store.KeyByHash(ctx, "hash1"); !got.Disabled {
+		t.Error("update is not persisted")
+	}
+	if _, err := store.UpdateKey(ctx, "missing", func(*model.APIKey) error { return nil }); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("update missing err = %v, want ErrNotFound", err)
+	}
+
+	// Удаление убирает ключ, его хеш и счётчики, не трогая другие ключи
+	if _, err := store.ConsumeRequest(ctx, "k1", "2026-01-02", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteKey(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.KeyByHash(ctx, "hash1"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("deleted key hash err = %v, want ErrNotFound", err)
+	}
+	if days, _ := store.Usage(ctx, "k1", "2026-01-01", "2026-12-31"); len(days) != 0 {
+		t.Errorf("usage of deleted key = %+v", days)
+	}
+	if err := store.DeleteKey(ctx, "k1"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("second delete err = %v, want ErrNotFound", err)
+	}
+	keys, err := store.Keys(ctx)
+	if err != nil || len(keys) != 1 || keys[0].ID != "k2" {
+		t.Errorf("Keys = %+v, %v", keys, err)
+	}
+}
+
+func TestAPIKeyQuota(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewAPIKeyStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		usage, err := store.ConsumeRequest(ctx, "k1", "2026-01-02", 3)
+		if err != nil || usage.Requests != i {
+			t.Fatalf("request %d: %+v, %v", i, usage, err)
+		}
+	}
+	if _, err := store.ConsumeRequest(ctx, "k1", "2026-01-02", 3); !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("over quota err = %v, want ErrQuotaExceeded", err)
+	}
+	// Отклонённый запрос не учитывается, квота считается по дням, 0 — без ограничений
+	if usage, err := store.ConsumeRequest(ctx, "k1", "2026-01-03", 3); err != nil || usage.Requests != 1 {
+		t.Errorf("next day: %+v, %v", usage, err)
+	}
+	if usage, err := store.ConsumeRequest(ctx, "k1", "2026-01-02", 0); err != nil || usage.Requests != 4 {
+		t.Errorf("unlimited: %+v, %v", usage, err)
+	}
+
+	for _, calls := range []map[string]int{{"geoapify": 1}, {"geoapify": 1, "openweather": 1}} {
+		if err := store.AddProviderCalls(ctx, "k1", "2026-01-03", calls); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	days, err := store.Usage(ctx, "k1", "2026-01-01", "2026-01-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 || days[0].Day != "2026-01-02" || days[0].Requests != 4 || days[1].Requests != 1 {
+		t.Fatalf("usage = %+v", days)
+	}
+	if days[1].ProviderCalls["geoapify"] != 2 || days[1].ProviderCalls["openweather"] != 1 {
+		t.Errorf("provider calls = %v", days[1].ProviderCalls)
+	}
+	if days, _ := store.Usage(ctx, "k1", "2026-01-03", "2026-01-03"); len(days) != 1 {
+		t.Errorf("single day usage = %+v", days)
+	}
+	if days, err := store.Usage(ctx, "unknown", "2026-01-01", "2026-01-03"); err != nil || len(days) != 0 {
+		t.Errorf("unknown key usage = %+v, %v", days, err)
+	}
+}
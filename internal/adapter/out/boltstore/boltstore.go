@@ -0,0 +1,13 @@
+package boltstore
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Open открывает (или создаёт) файл встроенной базы данных.
+// Один файл разделяется всеми хранилищами приложения, каждое в своём bucket
+func Open(path string) (*bolt.DB, error) {
+	return bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+}
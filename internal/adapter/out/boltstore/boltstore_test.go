@@ -0,0 +1,19 @@
+package boltstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTestDB открывает базу во временном каталоге теста
+func openTestDB(t *testing.T) *bolt.DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "places.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
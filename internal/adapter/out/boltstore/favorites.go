@@ -0,0 +1,150 @@
+package boltstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"places/internal/model"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var favoritesBucket = []byte("favorites")
+
+// FavoritesStore хранит списки избранного: bucket пользователя -> имя списка -> JSON
+type FavoritesStore struct {
+	db *bolt.DB
+}
+
+func NewFavoritesStore(db *bolt.DB) (*FavoritesStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(favoritesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FavoritesStore{db: db}, nil
+}
+
+func (s *FavoritesStore) Lists(_ context.Context, userID string) ([]model.FavoriteList, error) {
+	lists := make([]model.FavoriteList, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		user := tx.Bucket(favoritesBucket).Bucket([]byte(userID))
+		if user == nil {
+			return nil
+		}
+		return user.ForEach(func(_, v []byte) error {
+			var list model.FavoriteList
+			if err := json.Unmarshal(v, &list); err != nil {
+				return err
+			}
+			lists = append(lists, list)
+			return nil
+		})
+	})
+	return lists, err
+}
+
+func (s *FavoritesStore) List(_ context.Context, userID, name string) (*model.FavoriteList, error) {
+	var list *model.FavoriteList
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		list, err = getList(tx.Bucket(favoritesBucket).Bucket([]byte(userID)), name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *FavoritesStore) UpdateList(_ context.Context, userID, name string, fn func(list *model.FavoriteList) error) (*model.FavoriteList, error) {
+	var list *model.FavoriteList
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		user, err := tx.Bucket(favoritesBucket).CreateBucketIfNotExists([]byte(userID))
+		if err != nil {
+			return err
+		}
+
+		// Новый список создаётся только вместо отсутствующего: испорченный не перезаписываем
+		list, err = getList(user, name)
+		if errors.Is(err, model.ErrNotFound) {
+			list = &model.FavoriteList{
+				Name:      name,
+				Locations: []model.Location{},
+				Places:    []model.Place{},
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if err := fn(list); err != nil {
+			return err
+		}
+		list.UpdatedAt = time.Now().UTC()
+
+		return putList(user, list)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *FavoritesStore) RenameList(_ context.Context, userID, oldName, newName string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		user := tx.Bucket(favoritesBucket).Bucket([]byte(userID))
+
+		list, err := getList(user, oldName)
+		if err != nil {
+			return err
+		}
+		if user.Get([]byte(newName)) != nil {
+			return fmt.Errorf("favorites list %q: %w", newName, model.ErrAlreadyExists)
+		}
+
+		list.Name = newName
+		list.UpdatedAt = time.Now().UTC()
+		if err := putList(user, list); err != nil {
+			return err
+		}
+		return user.Delete([]byte(oldName))
+	})
+}
+
+func (s *FavoritesStore) DeleteList(_ context.Context, userID, name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		user := tx.Bucket(favoritesBucket).Bucket([]byte(userID))
+		if user == nil || user.Get([]byte(name)) == nil {
+			return fmt.Errorf("favorites list %q: %w", name, model.ErrNotFound)
+		}
+		return user.Delete([]byte(name))
+	})
+}
+
+func getList(user *bolt.Bucket, name string) (*model.FavoriteList, error) {
+	if user == nil {
+		return nil, fmt.Errorf("favorites list %q: %w", name, model.ErrNotFound)
+	}
+	data := user.Get([]byte(name))
+	if data == nil {
+		return nil, fmt.Errorf("favorites list %q: %w", name, model.ErrNotFound)
+	}
+
+	var list model.FavoriteList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func putList(user *bolt.Bucket, list *model.FavoriteList) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return user.Put([]byte(list.Name), data)
+}
@@ -0,0 +1,130 @@
+package boltstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"places/internal/model"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestFavoritesStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFavoritesStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lists, err := store.Lists(ctx, "k1/alice"); err != nil || len(lists) != 0 {
+		t.Fatalf("Lists of a new user = %+v, %v", lists, err)
+	}
+	if _, err := store.List(ctx, "k1/alice", "trip"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("missing list err = %v, want ErrNotFound", err)
+	}
+
+	// UpdateList создаёт список при первом изменении
+	created, err := store.UpdateList(ctx, "k1/alice", "trip", func(l *model.FavoriteList) error {
+		l.Locations = append(l.Locations, model.Location{Name: "Москва", Lat: 55.7558, Lon: 37.6173})
+		l.Places = append(l.Places, model.Place{Xid: "W1", Name: "Кремль"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt is not set")
+	}
+	got, err := store.List(ctx, "k1/alice", "trip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "trip" || len(got.Locations) != 1 || got.Locations[0].Name != "Москва" || len(got.Places) != 1 || got.Places[0].Xid != "W1" {
+		t.Errorf("List = %+v", got)
+	}
+
+	// Ошибка fn откатывает транзакцию
+	failure := errors.New("rejected")
+	if _, err := store.UpdateList(ctx, "k1/alice", "trip", func(l *model.FavoriteList) error {
+		l.Places = nil
+		return failure
+	}); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want %v", err, failure)
+	}
+	if got, _ := store.List(ctx, "k1/alice", "trip"); len(got.Places) != 1 {
+		t.Error("failed update is persisted")
+	}
+
+	// Пользователи разных ключей не видят списков друг друга
+	if lists, _ := store.Lists(ctx, "k2/alice"); len(lists) != 0 {
+		t.Errorf("other user's lists = %+v", lists)
+	}
+
+	if _, err := store.UpdateList(ctx, "k1/alice", "later", func(*model.FavoriteList) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenameList(ctx, "k1/alice", "trip", "later"); !errors.Is(err, model.ErrAlreadyExists) {
+		t.Errorf("rename onto existing err = %v, want ErrAlreadyExists", err)
+	}
+	if err := store.RenameList(ctx, "k1/alice", "trip", "moscow"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.List(ctx, "k1/alice", "trip"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("old name err = %v, want ErrNotFound", err)
+	}
+	if got, err := store.List(ctx, "k1/alice", "moscow"); err != nil || got.Name != "moscow" || len(got.Places) != 1 {
+		t.Errorf("renamed list = %+v, %v", got, err)
+	}
+	if err := store.RenameList(ctx, "k2/alice", "moscow", "x"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("rename for unknown user err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.DeleteList(ctx, "k1/alice", "moscow"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteList(ctx, "k1/alice", "moscow"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("second delete err = %v, want ErrNotFound", err)
+	}
+	if lists, _ := store.Lists(ctx, "k1/alice"); len(lists) != 1 || lists[0].Name != "later" {
+		t.Errorf("Lists = %+v", lists)
+	}
+}
+
+func TestFavoritesUpdateCorruptedList(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	store, err := NewFavoritesStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		user, err := tx.Bucket(favoritesBucket).CreateBucketIfNotExists([]byte("k1/alice"))
+		if err != nil {
+			return err
+		}
+		return user.Put([]byte("trip"), []byte(`{"name":"trip","locations":[{"na`))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	_, err = store.UpdateList(ctx, "k1/alice", "trip", func(*model.FavoriteList) error {
+		called = true
+		return nil
+	})
+	if err == nil || errors.Is(err, model.ErrNotFound) || called {
+		t.Fatalf("err = %v, fn called = %v, want decode error", err, called)
+	}
+	// Испорченная запись осталась как была
+	err = db.View(func(tx *bolt.Tx) error {
+		if got := string(tx.Bucket(favoritesBucket).Bucket([]byte("k1/alice")).Get([]byte("trip"))); got != `{"name":"trip","locations":[{"na` {
+			t.Errorf("stored list overwritten: %s", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
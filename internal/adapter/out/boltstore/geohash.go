@@ -0,0 +1,98 @@
+package boltstore
+
+import (
+	"math"
+
+	"places/internal/model"
+)
+
+/*
+	Geohash делит мир на вложенные прямоугольные ячейки: чем длиннее
+	строка, тем меньше ячейка, а точки одной ячейки имеют общий префикс.
+	Поэтому выборка по области — это несколько обходов курсором по префиксам
+*/
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohash кодирует точку строкой длины precision
+func geohash(lat, lon float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+
+	hash := make([]byte, 0, precision)
+	bit, ch := 0, 0
+	even := true
+	for len(hash) < precision {
+		if even {
+			mid := (minLon + maxLon) / 2
+			if lon >= mid {
+				ch |= 1 << (4 - bit)
+				minLon = mid
+			} else {
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+		hash = append(hash, geohashAlphabet[ch])
+		bit, ch = 0, 0
+	}
+	return string(hash)
+}
+
+// geohashCellSize возвращает высоту и ширину ячейки длины precision в градусах
+func geohashCellSize(precision int) (lat, lon float64) {
+	bits := precision * 5
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Exp2(float64(latBits)), 360 / math.Exp2(float64(lonBits))
+}
+
+// geohashCover возвращает не больше maxCells ячеек, покрывающих прямоугольник.
+// Длина ячеек — precision или меньше, если при ней ячеек было бы больше maxCells
+func geohashCover(bbox model.BBox, precision, maxCells int) []string {
+	minLat, maxLat := math.Max(bbox.South, -90), math.Min(bbox.North, 90)
+	minLon, maxLon := math.Max(bbox.West, -180), math.Min(bbox.East, 180)
+
+	cellLat, cellLon := geohashCellSize(precision)
+	for precision > 1 && geohashCellCount(minLat, maxLat, cellLat)*geohashCellCount(minLon, maxLon, cellLon) > maxCells {
+		precision--
+		cellLat, cellLon = geohashCellSize(precision)
+	}
+
+	seen := make(map[string]bool)
+	var cells []string
+	// Шаг не больше ячейки, а последний ряд и столбец берутся по краю прямоугольника
+	for y := minLat; ; y = math.Min(y+cellLat, maxLat) {
+		for x := minLon; ; x = math.Min(x+cellLon, maxLon) {
+			if cell := geohash(y, x, precision); !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+			if x >= maxLon {
+				break
+			}
+		}
+		if y >= maxLat {
+			break
+		}
+	}
+	return cells
+}
+
+// geohashCellCount — сколько ячеек размера cell задевает отрезок от min до max
+func geohashCellCount(min, max, cell float64) int {
+	return int(math.Floor(max/cell)-math.Floor(min/cell)) + 1
+}
@@ -0,0 +1,84 @@
+package boltstore
+
+import (
+	"strings"
+	"testing"
+
+	"places/internal/model"
+)
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{57.64911, 10.40744, 5, "u4pru"},
+		{-33.8688, 151.2093, 6, "r3gx2f"},
+		{0, 0, 1, "s"},
+	}
+	for _, tt := range tests {
+		if got := geohash(tt.lat, tt.lon, tt.precision); got != tt.want {
+			t.Errorf("geohash(%v, %v, %d) = %q, want %q", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestGeohashCellSize(t *testing.T) {
+	lat, lon := geohashCellSize(5)
+	// 25 бит: 13 на долготу и 12 на широту
+	if lat != 180.0/4096 || lon != 360.0/8192 {
+		t.Errorf("geohashCellSize(5) = %v, %v", lat, lon)
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	bbox := model.CircleBounds(55.03, 82.92, 2000)
+	cells := geohashCover(bbox, queryPrecision, maxQueryCells)
+
+	// Каждая точка прямоугольника попадает в одну из ячеек
+	for _, point := range [][2]float64{
+		{bbox.South, bbox.West}, {bbox.South, bbox.East}, {bbox.North, bbox.West}, {bbox.North, bbox.East},
+		{55.03, 82.92}, {(bbox.South + 55.03) / 2, (bbox.East + 82.92) / 2},
+	} {
+		hash := geohash(point[0], point[1], pointPrecision)
+		found := false
+		for _, cell := range cells {
+			if strings.HasPrefix(hash, cell) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("point %v (%s) is not covered by %v", point, hash, cells)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, cell := range cells {
+		if len(cell) != queryPrecision || seen[cell] {
+			t.Errorf("cell %q is duplicated or has wrong precision", cell)
+		}
+		seen[cell] = true
+	}
+}
+
+func TestGeohashCoverIsBounded(t *testing.T) {
+	tests := []struct {
+		name string
+		bbox model.BBox
+	}{
+		{"city", model.BBox{West: 82.8, South: 54.9, East: 83.1, North: 55.1}},
+		{"largest area", model.BBox{West: 82, South: 54, East: 84, North: 56}},
+		{"whole world", model.BBox{West: -180, South: -90, East: 180, North: 90}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := geohashCover(tt.bbox, queryPrecision, maxQueryCells)
+			if len(cells) == 0 || len(cells) > maxQueryCells {
+				t.Errorf("%d cells, want 1..%d", len(cells), maxQueryCells)
+			}
+		})
+	}
+}
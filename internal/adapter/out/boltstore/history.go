@@ -0,0 +1,116 @@
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"places/internal/model"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	historyBucket      = []byte("history")
+	historyUsersBucket = []byte("history_users")
+)
+
+// HistoryStore хранит историю запросов. Ключи — время записи в наносекундах
+// и порядковый номер, поэтому курсор обходит записи в хронологическом порядке.
+// Записи пользователя дублируются в его bucket, чтобы не сканировать всю историю
+type HistoryStore struct {
+	db *bolt.DB
+}
+
+func NewHistoryStore(db *bolt.DB) (*HistoryStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(historyBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(historyUsersBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryStore{db: db}, nil
+}
+
+func (s *HistoryStore) Record(_ context.Context, entry model.HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		history := tx.Bucket(historyBucket)
+		seq, err := history.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := historyKey(entry.Time, seq)
+
+		if err := history.Put(key, data); err != nil {
+			return err
+		}
+		if entry.UserID == "" {
+			return nil
+		}
+
+		user, err := tx.Bucket(historyUsersBucket).CreateBucketIfNotExists([]byte(entry.UserID))
+		if err != nil {
+			return err
+		}
+		return user.Put(key, data)
+	})
+}
+
+func (s *HistoryStore) UserHistory(_ context.Context, userID string, limit int) ([]model.HistoryEntry, error) {
+	entries := make([]model.HistoryEntry, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		user := tx.Bucket(historyUsersBucket).Bucket([]byte(userID))
+		if user == nil {
+			return nil
+		}
+
+		c := user.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if limit > 0 && len(entries) >= limit {
+				break
+			}
+			var entry model.HistoryEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+func (s *HistoryStore) Entries(_ context.Context, from, to time.Time) ([]model.HistoryEntry, error) {
+	entries := make([]model.HistoryEntry, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(historyBucket).Cursor()
+		end := historyKey(to, 0)
+
+		for k, v := c.Seek(historyKey(from, 0)); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+			var entry model.HistoryEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+func historyKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
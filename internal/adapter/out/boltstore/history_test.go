@@ -0,0 +1,61 @@
+package boltstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+func TestHistoryStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewHistoryStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []model.HistoryEntry{
+		{Kind: model.HistorySearch, UserID: "k1/alice", Query: "Москва", ResultCount: 5, Time: start},
+		{Kind: model.HistorySearch, Query: "анонимный", Time: start.Add(time.Minute)},
+		{Kind: model.HistoryLocation, UserID: "k1/alice", Location: &model.Location{Name: "Москва", Lat: 55.7558, Lon: 37.6173}, Time: start.Add(2 * time.Minute)},
+		// Одинаковое время различается порядковым номером
+		{Kind: model.HistorySearch, UserID: "k1/alice", Query: "Тверь", Time: start.Add(2 * time.Minute)},
+		{Kind: model.HistorySearch, UserID: "k2/alice", Query: "Казань", Time: start.Add(time.Hour)},
+	}
+	for _, e := range entries {
+		if err := store.Record(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := store.UserHistory(ctx, "k1/alice", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].Query != "Тверь" || history[1].Kind != model.HistoryLocation || history[2].Query != "Москва" {
+		t.Fatalf("history = %+v", history)
+	}
+	if history[1].Location == nil || history[1].Location.Lat != 55.7558 || !history[2].Time.Equal(start) || history[2].ResultCount != 5 {
+		t.Errorf("entries do not round-trip: %+v", history)
+	}
+	if history, _ := store.UserHistory(ctx, "k1/alice", 2); len(history) != 2 || history[0].Query != "Тверь" {
+		t.Errorf("limited history = %+v", history)
+	}
+	if history, _ := store.UserHistory(ctx, "unknown", 10); len(history) != 0 {
+		t.Errorf("unknown user history = %+v", history)
+	}
+
+	// Полуинтервал [from, to): запись ровно в to не входит
+	all, err := store.Entries(ctx, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].Query != "Москва" || all[1].Query != "анонимный" {
+		t.Errorf("entries = %+v", all)
+	}
+	if late, _ := store.Entries(ctx, start.Add(time.Hour), start.Add(2*time.Hour)); len(late) != 1 || late[0].Query != "Казань" {
+		t.Errorf("late entries = %+v", late)
+	}
+}
@@ -0,0 +1,220 @@
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"places/internal/model"
+)
+
+var (
+	placesIndexBucket    = []byte("places_index")
+	placesCoverageBucket = []byte("places_coverage")
+)
+
+const (
+	// pointPrecision — длина geohash в ключе записи, ячейка около 5×5 м
+	pointPrecision = 9
+	// queryPrecision — длина префикса при выборке, ячейка около 5×5 км
+	queryPrecision = 5
+	// maxQueryCells — наибольшее число обходов курсором за выборку: для больших
+	// областей префиксы укорачиваются, а лишние записи отсекает вызывающий
+	maxQueryCells = 100
+	// defaultLanguageBucket — bucket для ответов на языке провайдера по умолчанию
+	defaultLanguageBucket = "default"
+)
+
+// PlacesIndexStore — пространственный индекс мест на geohash. Внутри bucket языка
+// ключи мест — geohash точки и xid, ключи покрытий — geohash центра и радиус,
+// поэтому места и покрытия одной области лежат рядом и выбираются по префиксу
+type PlacesIndexStore struct {
+	db *bolt.DB
+}
+
+// indexedPlace — запись места с временем получения от провайдера
+type indexedPlace struct {
+	model.Place
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+func NewPlacesIndexStore(db *bolt.DB) (*PlacesIndexStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(placesIndexBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(placesCoverageBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PlacesIndexStore{db: db}, nil
+}
+
+// SavePlaces перезаписывает места со временем fetchedAt. Места, которых нет в свежем
+// ответе, не удаляются сразу: их отсекает since при чтении, а удаляет Prune
+func (s *PlacesIndexStore) SavePlaces(_ context.Context, coverage *model.PlacesCoverage, lang string, fetchedAt time.Time, places []model.Place) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		index, err := tx.Bucket(placesIndexBucket).CreateBucketIfNotExists(languageBucket(lang))
+		if err != nil {
+			return err
+		}
+		for _, place := range places {
+			data, err := json.Marshal(indexedPlace{Place: place, IndexedAt: fetchedAt})
+			if err != nil {
+				return err
+			}
+			if err := index.Put(pointKey(place.Lat, place.Lon, place.Xid), data); err != nil {
+				return err
+			}
+		}
+
+		if coverage == nil {
+			return nil
+		}
+		coverageData, err := json.Marshal(coverage)
+		if err != nil {
+			return err
+		}
+		coverages, err := tx.Bucket(placesCoverageBucket).CreateBucketIfNotExists(languageBucket(lang))
+		if err != nil {
+			return err
+		}
+		radius := strconv.FormatFloat(coverage.Radius, 'f', -1, 64)
+		return coverages.Put(pointKey(coverage.Lat, coverage.Lon, radius), coverageData)
+	})
+}
+
+func (s *PlacesIndexStore) Places(_ context.Context, bbox model.BBox, lang string, since time.Time) ([]model.Place, error) {
+	places := make([]model.Place, 0)
+	err := s.scan(placesIndexBucket, bbox, lang, func(v []byte) error {
+		var place indexedPlace
+		if err := json.Unmarshal(v, &place); err != nil {
+			return err
+		}
+		if !place.IndexedAt.Before(since) {
+			places = append(places, place.Place)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+func (s *PlacesIndexStore) Coverages(_ context.Context, bbox model.BBox, lang string) ([]model.PlacesCoverage, error) {
+	coverages := make([]model.PlacesCoverage, 0)
+	err := s.scan(placesCoverageBucket, bbox, lang, func(v []byte) error {
+		var coverage model.PlacesCoverage
+		if err := json.Unmarshal(v, &coverage); err != nil {
+			return err
+		}
+		coverages = append(coverages, coverage)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// Prune удаляет места и покрытия всех языков, полученные раньше before. Место,
+// которое не вернул ни один ответ новее before, лежит вне живых покрытий
+func (s *PlacesIndexStore) Prune(_ context.Context, before time.Time) (int, error) {
+	pruned := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for bucket, fetchedAt := range map[string]func(v []byte) (time.Time, error){
+			string(placesIndexBucket):    placeIndexedAt,
+			string(placesCoverageBucket): coverageFetchedAt,
+		} {
+			root := tx.Bucket([]byte(bucket))
+			err := root.ForEachBucket(func(name []byte) error {
+				n, err := deleteFetchedBefore(root.Bucket(name), before, fetchedAt)
+				pruned += n
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+// deleteFetchedBefore удаляет записи bucket, полученные раньше before
+func deleteFetchedBefore(b *bolt.Bucket, before time.Time, fetchedAt func(v []byte) (time.Time, error)) (int, error) {
+	var stale [][]byte
+	// Ключи собираются заранее: удаление под курсором сбивает его обход
+	err := b.ForEach(func(k, v []byte) error {
+		t, err := fetchedAt(v)
+		if err != nil {
+			return err
+		}
+		if t.Before(before) {
+			stale = append(stale, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, k := range stale {
+		if err := b.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
+
+func placeIndexedAt(v []byte) (time.Time, error) {
+	var place indexedPlace
+	err := json.Unmarshal(v, &place)
+	return place.IndexedAt, err
+}
+
+func coverageFetchedAt(v []byte) (time.Time, error) {
+	var coverage model.PlacesCoverage
+	err := json.Unmarshal(v, &coverage)
+	return coverage.FetchedAt, err
+}
+
+// scan обходит записи bucket языка в ячейках, покрывающих прямоугольник
+func (s *PlacesIndexStore) scan(bucket []byte, bbox model.BBox, lang string, fn func(v []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket).Bucket(languageBucket(lang))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for _, cell := range geohashCover(bbox, queryPrecision, maxQueryCells) {
+			prefix := []byte(cell)
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				if err := fn(v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func pointKey(lat, lon float64, id string) []byte {
+	return []byte(geohash(lat, lon, pointPrecision) + "/" + id)
+}
+
+func languageBucket(lang string) []byte {
+	if lang == "" {
+		return []byte(defaultLanguageBucket)
+	}
+	return []byte(lang)
+}
@@ -0,0 +1,169 @@
+package boltstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"places/internal/model"
+)
+
+func xids(places []model.Place) []string {
+	ids := make([]string, 0, len(places))
+	for _, place := range places {
+		ids = append(ids, place.Xid)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestPlacesIndexStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewPlacesIndexStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0.01° широты ≈ 1.1 км
+	fetched := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	coverage := &model.PlacesCoverage{Lat: 55, Lon: 83, Radius: 2000, FetchedAt: fetched}
+	err = store.SavePlaces(ctx, coverage, "ru", fetched, []model.Place{
+		{Xid: "center", Lat: 55, Lon: 83},
+		{Xid: "edge", Lat: 55.015, Lon: 83},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Без покрытия места только добавляются
+	if err := store.SavePlaces(ctx, nil, "ru", fetched.Add(time.Hour), []model.Place{{Xid: "outside", Lat: 55.05, Lon: 83}}); err != nil {
+		t.Fatal(err)
+	}
+
+	places, err := store.Places(ctx, model.CircleBounds(55, 83, 2000), "ru", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := xids(places); len(got) < 2 || got[0] != "center" || got[1] != "edge" {
+		t.Errorf("places = %v, want center and edge", got)
+	}
+	if places, _ := store.Places(ctx, model.CircleBounds(55, 83, 2000), "en", time.Time{}); len(places) != 0 {
+		t.Errorf("other language: %v", xids(places))
+	}
+	if places, _ := store.Places(ctx, model.BBox{West: 10, South: 10, East: 11, North: 11}, "ru", time.Time{}); len(places) != 0 {
+		t.Errorf("far away: %v", xids(places))
+	}
+
+	coverages, err := store.Coverages(ctx, model.CircleBounds(55.001, 83, 1000), "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverages) != 1 || coverages[0].Radius != 2000 || !coverages[0].FetchedAt.Equal(fetched) {
+		t.Errorf("coverages = %+v", coverages)
+	}
+
+	// После обновления покрытия места, которых нет в свежем ответе, отсекает since
+	refreshedAt := fetched.Add(2 * time.Hour)
+	refreshed := &model.PlacesCoverage{Lat: 55, Lon: 83, Radius: 2000, FetchedAt: refreshedAt}
+	if err := store.SavePlaces(ctx, refreshed, "ru", refreshedAt, []model.Place{{Xid: "center", Lat: 55, Lon: 83}}); err != nil {
+		t.Fatal(err)
+	}
+	places, err = store.Places(ctx, model.CircleBounds(55, 83, 2000), "ru", refreshedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := xids(places); len(got) != 1 || got[0] != "center" {
+		t.Errorf("after refresh places = %v, want center", got)
+	}
+	places, err = store.Places(ctx, model.CircleBounds(55.02, 83, 5000), "ru", fetched.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := xids(places); len(got) != 2 || got[0] != "center" || got[1] != "outside" {
+		t.Errorf("places since the area search = %v, want center and outside", got)
+	}
+}
+
+// countRecords считает записи во всех bucket языков
+func countRecords(t *testing.T, store *PlacesIndexStore, bucket []byte) int {
+	t.Helper()
+	n := 0
+	err := store.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(bucket)
+		return root.ForEachBucket(func(name []byte) error {
+			n += root.Bucket(name).Stats().KeyN
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewPlacesIndexStore(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		lang string
+		lat  float64
+		age  time.Duration
+	}{
+		{"ru", 55, 3 * time.Hour},
+		{"ru", 56, 10 * time.Minute},
+		{"en", 55, 2 * time.Hour},
+	} {
+		fetchedAt := now.Add(-c.age)
+		coverage := &model.PlacesCoverage{Lat: c.lat, Lon: 83, Radius: 1000, FetchedAt: fetchedAt}
+		places := []model.Place{
+			{Xid: fmt.Sprintf("%s-%v-a", c.lang, c.lat), Lat: c.lat, Lon: 83},
+			{Xid: fmt.Sprintf("%s-%v-b", c.lang, c.lat), Lat: c.lat + 0.001, Lon: 83},
+		}
+		if err := store.SavePlaces(ctx, coverage, c.lang, fetchedAt, places); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Место из давнего поиска в области не лежит ни в одном покрытии
+	if err := store.SavePlaces(ctx, nil, "ru", now.Add(-5*time.Hour), []model.Place{{Xid: "area", Lat: 50, Lon: 83}}); err != nil {
+		t.Fatal(err)
+	}
+	if places, coverages := countRecords(t, store, placesIndexBucket), countRecords(t, store, placesCoverageBucket); places != 7 || coverages != 3 {
+		t.Fatalf("before prune: %d places, %d coverages", places, coverages)
+	}
+
+	pruned, err := store.Prune(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Два покрытия, их четыре места и место поиска в области
+	if pruned != 7 {
+		t.Errorf("pruned = %d, want 7", pruned)
+	}
+	if places, coverages := countRecords(t, store, placesIndexBucket), countRecords(t, store, placesCoverageBucket); places != 2 || coverages != 1 {
+		t.Errorf("after prune: %d places, %d coverages, want 2 and 1", places, coverages)
+	}
+	for lang, want := range map[string]int{"ru": 1, "en": 0} {
+		coverages, err := store.Coverages(ctx, model.BBox{West: 82, South: 54, East: 84, North: 56.5}, lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(coverages) != want {
+			t.Errorf("%s coverages = %+v, want %d", lang, coverages, want)
+		}
+	}
+	places, err := store.Places(ctx, model.BBox{West: 82, South: 49, East: 84, North: 56.5}, "ru", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := xids(places); len(got) != 2 || got[0] != "ru-56-a" || got[1] != "ru-56-b" {
+		t.Errorf("places after prune = %v", got)
+	}
+}
@@ -0,0 +1,189 @@
+// Package fixture записывает ответы провайдеров на диск и воспроизводит их без сети.
+// Транспорт подключается ко всем адаптерам через http.RoundTripper
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Mode — режим работы транспорта
+type Mode string
+
+const (
+	// ModeOff — обычные запросы в сеть
+	ModeOff Mode = ""
+	// ModeRecord — запросы идут в сеть, ответы сохраняются на диск
+	ModeRecord Mode = "record"
+	// ModeReplay — ответы берутся только с диска, сеть не используется
+	ModeReplay Mode = "replay"
+)
+
+// redacted подставляется вместо секретов в сохранённых запросах
+const redacted = "REDACTED"
+
+// secretParams — параметры запроса, в которых провайдеры принимают ключи API
+var secretParams = []string{"key", "apiKey", "appid"}
+
+// Fixture — сохранённая пара запрос/ответ
+type Fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Transport записывает или воспроизводит ответы в каталоге dir
+type Transport struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+}
+
+// NewTransport создаёт транспорт в заданном режиме. next используется для
+// реальных запросов в режимах ModeOff и ModeRecord; nil — http.DefaultTransport
+func NewTransport(mode Mode, dir string, next http.RoundTripper) (*Transport, error) {
+	switch mode {
+	case ModeOff, ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown fixture mode: %q", mode)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if mode == ModeRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Transport{mode: mode, dir: dir, next: next}, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch t.mode {
+	case ModeReplay:
+		return t.replay(req)
+	case ModeRecord:
+		return t.record(req)
+	default:
+		return t.next.RoundTrip(req)
+	}
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Method: req.Method,
+		URL:    redactURL(req.URL),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+		Body:   redactSecrets(string(body), req.URL),
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		fixture.Header.Set("Content-Type", contentType)
+	}
+
+	// Без экранирования HTML записи остаются читаемыми в диффах
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(t.path(req), data.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("write fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(t.path(req))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no fixture for %s %s", req.Method, redactURL(req.URL))
+		}
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fixture.Header,
+		Body:          io.NopCloser(strings.NewReader(fixture.Body)),
+		ContentLength: int64(len(fixture.Body)),
+		Request:       req,
+	}, nil
+}
+
+// path — имя файла не зависит от ключа API, поэтому записи, сделанные
+// с настоящими ключами, воспроизводятся без них
+func (t *Transport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + redactURL(req.URL)))
+	return filepath.Join(t.dir, req.URL.Hostname()+"_"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// redactURL заменяет ключи API и упорядочивает параметры запроса
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	query := u.Query()
+	for _, name := range secretParams {
+		if query.Has(name) {
+			query.Set(name, redacted)
+		}
+	}
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+// redactSecrets убирает из тела ответа ключи, если провайдер их повторил.
+// Заменяется только значение секретного параметра — в повторённом адресе
+// (key=...) или в JSON ("key":"..."): короткий ключ может совпасть с данными ответа
+func redactSecrets(body string, u *url.URL) string {
+	query := u.Query()
+	for _, name := range secretParams {
+		secret := query.Get(name)
+		if secret == "" {
+			continue
+		}
+		name := regexp.QuoteMeta(name)
+		value := regexp.QuoteMeta(secret)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			value = "(?:" + value + "|" + regexp.QuoteMeta(escaped) + ")"
+		}
+		pattern := regexp.MustCompile(`(\b` + name + `=|"` + name + `"\s*:\s*")` + value + `([&"#\s\\]|$)`)
+		body = pattern.ReplaceAllString(body, "${1}"+redacted+"${2}")
+	}
+	return body
+}
@@ -0,0 +1,172 @@
+package fixture
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingTransport проверяет, что воспроизведение не ходит в сеть
+type failingTransport struct{ t *testing.T }
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected network request %s", req.URL)
+	return nil, errors.New("network is disabled")
+}
+
+func get(t *testing.T, transport http.RoundTripper, rawURL string) (*http.Response, string) {
+	t.Helper()
+	resp, err := (&http.Client{Transport: transport}).Get(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=1")
+		_, _ = w.Write([]byte(`{"name":"Кафе","q":"` + r.URL.Query().Get("q") + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	recorder, err := NewTransport(ModeRecord, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, body := get(t, recorder, server.URL+"/api?q=cafe&key=real-secret")
+	if resp.StatusCode != http.StatusOK || body != `{"name":"Кафе","q":"cafe"}` {
+		t.Fatalf("record returned %d %s", resp.StatusCode, body)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("fixtures = %v, want one file", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "real-secret") || !strings.Contains(string(data), "key="+redacted) {
+		t.Errorf("fixture keeps the API key: %s", data)
+	}
+	if strings.Contains(string(data), "Set-Cookie") {
+		t.Errorf("fixture keeps unrelated headers: %s", data)
+	}
+
+	// Воспроизведение с другим ключом и другим порядком параметров находит ту же запись
+	replayer, err := NewTransport(ModeReplay, dir, failingTransport{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, body = get(t, replayer, server.URL+"/api?key=other&q=cafe")
+	if resp.StatusCode != http.StatusOK || body != `{"name":"Кафе","q":"cafe"}` {
+		t.Errorf("replay returned %d %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	replayer, err := NewTransport(ModeReplay, t.TempDir(), failingTransport{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "https://api.example/v1?appid=secret", nil)
+	_, err = replayer.RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Fatalf("err = %v, want missing fixture error", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
+
+func TestNewTransportMode(t *testing.T) {
+	if _, err := NewTransport("replay-all", t.TempDir(), nil); err == nil {
+		t.Error("unknown mode: expected error")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	off, err := NewTransport(ModeOff, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, body := get(t, off, server.URL); body != "ok" {
+		t.Errorf("body = %q", body)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("ModeOff wrote fixtures: %v", files)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+		want string
+	}{
+		{
+			name: "echoed url",
+			url:  "https://api.example/geocode?q=x&key=abc123",
+			body: `{"next":"https://api.example/geocode?key=abc123&page=2"}`,
+			want: `{"next":"https://api.example/geocode?key=REDACTED&page=2"}`,
+		},
+		{
+			name: "json field",
+			url:  "https://api.example/places?apiKey=abc123",
+			body: `{"apiKey": "abc123","id":1}`,
+			want: `{"apiKey": "REDACTED","id":1}`,
+		},
+		{
+			name: "short key inside data is kept",
+			url:  "https://api.example/weather?appid=12",
+			body: `{"temp":12,"id":"a12b","url":"https://api.example/weather?appid=12"}`,
+			want: `{"temp":12,"id":"a12b","url":"https://api.example/weather?appid=REDACTED"}`,
+		},
+		{
+			name: "other parameters are not secrets",
+			url:  "https://api.example/geocode?q=Москва",
+			body: `{"q":"Москва","url":"?q=Москва"}`,
+			want: `{"q":"Москва","url":"?q=Москва"}`,
+		},
+		{
+			name: "prefix of a longer value is kept",
+			url:  "https://api.example/geocode?key=abc",
+			body: `{"url":"?key=abcdef"}`,
+			want: `{"url":"?key=abcdef"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := redactSecrets(tt.body, u); got != tt.want {
+				t.Errorf("redactSecrets() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package geoapify
+
+import (
+	"strings"
+
+	"places/internal/model"
+)
+
+// categoryMapping переводит категории Geoapify в таксономию сервиса.
+// Используется самый длинный совпавший префикс
+var categoryMapping = map[string]string{
+	"tourism":                           "sights",
+	"tourism.sights":                    "sights",
+	"tourism.attraction":                "sights",
+	"tourism.attraction.viewpoint":      "sights.viewpoint",
+	"tourism.sights.memorial":           "sights.monument",
+	"tourism.sights.place_of_worship":   "sights.religion",
+	"religion":                          "sights.religion",
+	"heritage":                          "sights",
+	"building.historic":                 "sights",
+	"entertainment":                     "entertainment",
+	"entertainment.museum":              "sights.museum",
+	"entertainment.culture":             "culture",
+	"entertainment.culture.theatre":     "culture.theatre",
+	"entertainment.culture.gallery":     "culture.gallery",
+	"entertainment.culture.arts_centre": "culture.gallery",
+	"entertainment.cinema":              "entertainment.cinema",
+	"entertainment.zoo":                 "entertainment.zoo",
+	"entertainment.aquarium":            "entertainment.zoo",
+	"entertainment.theme_park":          "entertainment.theme_park",
+	"catering":                          "food",
+	"catering.restaurant":               "food.restaurant",
+	"catering.cafe":                     "food.cafe",
+	"catering.bar":                      "food.bar",
+	"catering.pub":                      "food.bar",
+	"catering.biergarten":               "food.bar",
+	"catering.fast_food":                "food.fast_food",
+	"accommodation":                     "lodging",
+	"commercial":                        "shopping",
+	"commercial.shopping_mall":          "shopping.mall",
+	"leisure":                           "leisure",
+	"leisure.park":                      "leisure.park",
+	"natural":                           "nature",
+	"beach":                             "nature",
+	"national_park":                     "nature",
+	"sport":                             "sport",
+}
+
+// mapCategories переводит категории места в таксономию. Geoapify перечисляет
+// и категорию, и всех её предков ("catering", "catering.cafe"), поэтому
+// переводятся только самые конкретные из них
+func mapCategories(providerCategories []string, lang string) ([]model.CategoryRef, *model.CategoryRef) {
+	var ids []string
+	for _, category := range providerCategories {
+		if hasDescendant(providerCategories, category) {
+			continue
+		}
+		if id, ok := mapCategory(category); ok {
+			ids = append(ids, id)
+		}
+	}
+	return model.CategoryRefs(ids, lang)
+}
+
+func mapCategory(category string) (string, bool) {
+	for prefix := category; prefix != ""; {
+		if id, ok := categoryMapping[prefix]; ok {
+			return id, true
+		}
+		i := strings.LastIndex(prefix, ".")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return "", false
+}
+
+func hasDescendant(categories []string, category string) bool {
+	for _, other := range categories {
+		if strings.HasPrefix(other, category+".") {
+			return true
+		}
+	}
+	return false
+}
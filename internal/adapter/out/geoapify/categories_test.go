@@ -0,0 +1,73 @@
+package geoapify
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMapCategories(t *testing.T) {
+	tests := []struct {
+		name        string
+		categories  []string
+		lang        string
+		wantIDs     []string
+		wantPrimary string
+		wantLabel   string
+	}{
+		{
+			name:        "ancestors are dropped",
+			categories:  []string{"catering", "catering.cafe"},
+			lang:        "ru",
+			wantIDs:     []string{"food.cafe"},
+			wantPrimary: "food.cafe",
+			wantLabel:   "Кафе",
+		},
+		{
+			name:        "museum is a sight, not entertainment",
+			categories:  []string{"entertainment", "entertainment.museum"},
+			lang:        "en-US",
+			wantIDs:     []string{"sights.museum"},
+			wantPrimary: "sights.museum",
+			wantLabel:   "Museums",
+		},
+		{
+			name:        "unknown subcategory uses longest known prefix",
+			categories:  []string{"catering.restaurant.pizza", "tourism.sights"},
+			wantIDs:     []string{"food.restaurant", "sights"},
+			wantPrimary: "food.restaurant",
+			wantLabel:   "Restaurants",
+		},
+		{
+			name:        "duplicates are merged",
+			categories:  []string{"catering.bar", "catering.pub"},
+			wantIDs:     []string{"food.bar"},
+			wantPrimary: "food.bar",
+			wantLabel:   "Bars",
+		},
+		{
+			name:        "unknown categories fall back to other",
+			categories:  []string{"office.government"},
+			lang:        "de",
+			wantIDs:     []string{"other"},
+			wantPrimary: "other",
+			wantLabel:   "Other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, primary := mapCategories(tt.categories, tt.lang)
+
+			var ids []string
+			for _, ref := range refs {
+				ids = append(ids, ref.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if primary == nil || primary.ID != tt.wantPrimary || primary.Label != tt.wantLabel {
+				t.Errorf("primary = %+v, want %s (%s)", primary, tt.wantPrimary, tt.wantLabel)
+			}
+		})
+	}
+}
@@ -8,19 +8,46 @@ import (
 	"net/http"
 	"net/url"
 	"places/internal/model"
+	"strconv"
 	"strings"
 )
 
+// DefaultBaseURL — адрес API по умолчанию
+const DefaultBaseURL = "https://api.geoapify.com"
+
 type Client struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+// Option настраивает Client
+type Option func(*Client)
+
+// WithBaseURL направляет запросы на другой сервер, например на places-mock
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport подменяет HTTP-транспорт, например на запись и воспроизведение ответов
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
 		httpClient: &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Geoapify Places API response
@@ -32,6 +59,9 @@ type geoapifyPlacesResponse struct {
 			Categories []string `json:"categories"`
 			Datasource struct {
 				Sourcename string `json:"sourcename"`
+				Raw        struct {
+					Wikipedia string `json:"wikipedia"`
+				} `json:"raw"`
 			} `json:"datasource"`
 			Distance float64 `json:"distance"`
 		} `json:"properties"`
@@ -61,7 +91,6 @@ type geoapifyDetailsResponse struct {
 			Datasource   struct {
 				Sourcename string `json:"sourcename"`
 				Raw        struct {
-					Name         string `json:"name"`
 					Description  string `json:"description"`
 					Wikipedia    string `json:"wikipedia"`
 					Wikidata     string `json:"wikidata"`
@@ -83,43 +112,100 @@ type geoapifyDetailsResponse struct {
 	} `json:"features"`
 }
 
-func (c *Client) GetPlaces(ctx context.Context, lat, lon, radius float64) ([]model.Place, error) {
-	baseURL := "https://api.geoapify.com/v2/places"
+// placeCategories — категории Geoapify, из которых выбираются интересные места
+const placeCategories = "tourism.sights,entertainment,catering,accommodation,commercial,leisure,sport"
 
+// placesLimit — наибольшее число мест в одном ответе
+const placesLimit = 50
+
+func (c *Client) GetPlaces(ctx context.Context, lat, lon, radius float64, lang string) ([]model.Place, error) {
 	params := url.Values{}
-	params.Add("categories", "tourism.sights,entertainment,catering,accommodation,commercial,leisure,sport")
 	params.Add("filter", fmt.Sprintf("circle:%f,%f,%d", lon, lat, int(radius)))
 	params.Add("bias", fmt.Sprintf("proximity:%f,%f", lon, lat))
-	params.Add("limit", "50")
+	places, _, err := c.getPlaces(ctx, params, lang)
+	return places, err
+}
+
+// PolygonPages — сколько страниц описанного прямоугольника запрашивается для полигона
+const PolygonPages = 5
+
+// GetPlacesInArea ищет места в прямоугольнике через фильтр rect:. Фильтр geometry:
+// принимает только идентификатор геометрии, построенной самим Geoapify из изолиний
+// и границ, а произвольный полигон в нём не сохранить. Поэтому полигон запрашивается
+// по описанному прямоугольнику постранично, пока внутри не наберётся placesLimit мест
+// или не кончатся места прямоугольника; лишние места отсекаются здесь
+func (c *Client) GetPlacesInArea(ctx context.Context, area model.Area, lang string) ([]model.Place, error) {
+	bounds := area.Bounds()
+	filter := fmt.Sprintf("rect:%f,%f,%f,%f", bounds.West, bounds.South, bounds.East, bounds.North)
+	if area.Polygon == nil {
+		places, _, err := c.getPlaces(ctx, url.Values{"filter": {filter}}, lang)
+		return places, err
+	}
+
+	inside := make([]model.Place, 0)
+	for page := range PolygonPages {
+		params := url.Values{"filter": {filter}}
+		if page > 0 {
+			params.Set("offset", strconv.Itoa(page*placesLimit))
+		}
+		places, more, err := c.getPlaces(ctx, params, lang)
+		if err != nil {
+			return nil, err
+		}
+		for _, place := range places {
+			if area.Contains(place.Lat, place.Lon) {
+				inside = append(inside, place)
+			}
+			if len(inside) == placesLimit {
+				return inside, nil
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return inside, nil
+}
+
+// getPlaces выполняет запрос к Places API с фильтром области из params.
+// more сообщает, что ответ занял весь limit и у области может быть следующая страница
+func (c *Client) getPlaces(ctx context.Context, params url.Values, lang string) (places []model.Place, more bool, err error) {
+	baseURL := c.baseURL + "/v2/places"
+
+	params.Add("categories", placeCategories)
+	params.Add("limit", strconv.Itoa(placesLimit))
+	if lang != "" {
+		params.Add("lang", lang)
+	}
 	params.Add("apiKey", c.apiKey)
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("geoapify places API returned status: %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("geoapify places API returned status: %d", resp.StatusCode)
 	}
 
 	var gpResp geoapifyPlacesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&gpResp); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	places := make([]model.Place, 0, len(gpResp.Features))
+	places = make([]model.Place, 0, len(gpResp.Features))
 	for _, feature := range gpResp.Features {
 		props := feature.Properties
 		if props.Name == "" {
@@ -135,24 +221,33 @@ func (c *Client) GetPlaces(ctx context.Context, lat, lon, radius float64) ([]mod
 
 		categories := strings.Join(props.Categories, ", ")
 
+		refs, primary := mapCategories(props.Categories, lang)
+
 		places = append(places, model.Place{
-			Xid:      props.PlaceID,
-			Name:     props.Name,
-			Kinds:    categories,
-			Lat:      placeLatitude,
-			Lon:      placeLongitude,
-			Distance: props.Distance,
+			Xid:             props.PlaceID,
+			Name:            props.Name,
+			Kinds:           categories,
+			Categories:      refs,
+			PrimaryCategory: primary,
+			Lat:             placeLatitude,
+			Lon:             placeLongitude,
+			Distance:        props.Distance,
+			Wikipedia:       props.Datasource.Raw.Wikipedia,
+			Units:           model.UnitsMetric,
 		})
 	}
 
-	return places, nil
+	return places, len(gpResp.Features) == placesLimit, nil
 }
 
-func (c *Client) GetPlaceDetails(ctx context.Context, placeID string) (*model.Place, error) {
-	baseURL := "https://api.geoapify.com/v2/place-details"
+func (c *Client) GetPlaceDetails(ctx context.Context, placeID, lang string) (*model.Place, error) {
+	baseURL := c.baseURL + "/v2/place-details"
 
 	params := url.Values{}
 	params.Add("id", placeID)
+	if lang != "" {
+		params.Add("lang", lang)
+	}
 	params.Add("apiKey", c.apiKey)
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
@@ -197,11 +292,9 @@ func (c *Client) GetPlaceDetails(ctx context.Context, placeID string) (*model.Pl
 		placeLatitude = coords[1]
 	}
 
-	// Собираем описание из доступных полей
+	// Собираем описание из доступных полей. Название в описание не повторяется:
+	// рассказ о месте берётся из Википедии
 	description := props.Datasource.Raw.Description
-	if description == "" && props.Datasource.Raw.Name != "" {
-		description = props.Datasource.Raw.Name
-	}
 
 	// Добавляем адрес
 	address := props.Formatted
@@ -283,6 +376,8 @@ func (c *Client) GetPlaceDetails(ctx context.Context, placeID string) (*model.Pl
 	// Получаем изображение из raw данных
 	image := props.Datasource.Raw.Image
 
+	refs, primary := mapCategories(props.Categories, lang)
+
 	place := &model.Place{
 		Xid:         props.PlaceID,
 		Name:        props.Name,
@@ -293,6 +388,11 @@ func (c *Client) GetPlaceDetails(ctx context.Context, placeID string) (*model.Pl
 		Image:       image,
 		Wikipedia:   wikipedia,
 		WebSite:     website,
+		Units:       model.UnitsMetric,
+
+		OpeningHours:    props.Datasource.Raw.OpeningHours,
+		Categories:      refs,
+		PrimaryCategory: primary,
 	}
 
 	return place, nil
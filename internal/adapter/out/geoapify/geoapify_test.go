@@ -0,0 +1,284 @@
+package geoapify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"places/internal/mockserver"
+	"places/internal/model"
+	"places/internal/service/servicetest"
+)
+
+func TestGetPlaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []model.Place
+		wantErr bool
+	}{
+		{
+			name:   "full feature",
+			status: http.StatusOK,
+			body: `{"features":[{"properties":{"place_id":"p1","name":"Музей",
+				"categories":["entertainment","entertainment.museum"],"distance":310,
+				"datasource":{"raw":{"wikipedia":"ru:Музей"}}},
+				"geometry":{"coordinates":[83.09,54.84]}}]}`,
+			want: []model.Place{categorized(model.Place{
+				Xid: "p1", Name: "Музей", Kinds: "entertainment, entertainment.museum",
+				Lat: 54.84, Lon: 83.09, Distance: 310, Wikipedia: "ru:Музей", Units: model.UnitsMetric,
+			}, "sights.museum")},
+		},
+		{
+			name:   "empty name is skipped",
+			status: http.StatusOK,
+			body: `{"features":[
+				{"properties":{"place_id":"p1","name":""},"geometry":{"coordinates":[1,2]}},
+				{"properties":{"place_id":"p2","name":"Парк"},"geometry":{"coordinates":[3,4]}}]}`,
+			want: []model.Place{categorized(model.Place{Xid: "p2", Name: "Парк", Lat: 4, Lon: 3, Units: model.UnitsMetric})},
+		},
+		{
+			name:   "missing coordinates",
+			status: http.StatusOK,
+			body:   `{"features":[{"properties":{"place_id":"p1","name":"Кафе"},"geometry":{"coordinates":[83.09]}}]}`,
+			want:   []model.Place{categorized(model.Place{Xid: "p1", Name: "Кафе", Units: model.UnitsMetric})},
+		},
+		{
+			name:   "no features",
+			status: http.StatusOK,
+			body:   `{"features":[]}`,
+			want:   []model.Place{},
+		},
+		{
+			name:    "non-200",
+			status:  http.StatusBadRequest,
+			body:    `{"error":"Bad Request"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.status, tt.body)
+
+			got, err := client.GetPlaces(context.Background(), 54.84, 83.09, 2000, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPlaces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPlaces() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPlaceDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *model.Place
+		wantErr bool
+	}{
+		{
+			name:   "full feature",
+			status: http.StatusOK,
+			body: `{"features":[{"properties":{"place_id":"p1","name":"Кафе","categories":["catering.cafe"],
+				"formatted":"ул. Пирогова, 10","website":"https://site.example",
+				"datasource":{"raw":{"description":"Кофейня","website":"https://raw.example",
+					"wikipedia":"ru:Кафе","image":"https://img.example/1.jpg",
+					"opening_hours":"Mo-Su 08:00-22:00","cuisine":"coffee_shop","phone":"+7 000"}},
+				"contact":{"phone":"+7 383","email":"cafe@example.org"}},
+				"geometry":{"coordinates":[83.09,54.84]}}]}`,
+			want: categorizedPtr(model.Place{
+				Xid: "p1", Name: "Кафе", Kinds: "catering.cafe", Lat: 54.84, Lon: 83.09,
+				Description: "Кофейня\n\nAddress: ул. Пирогова, 10\nPhone: +7 383\nEmail: cafe@example.org" +
+					"\nOpening hours: Mo-Su 08:00-22:00\nCuisine: coffee_shop",
+				Image: "https://img.example/1.jpg", WebSite: "https://raw.example", Wikipedia: "ru:Кафе",
+				OpeningHours: "Mo-Su 08:00-22:00", Units: model.UnitsMetric,
+			}, "food.cafe"),
+		},
+		{
+			name:   "address from parts and fallbacks",
+			status: http.StatusOK,
+			body: `{"features":[{"properties":{"place_id":"p2","name":"Парк",
+				"address_line1":"Парк","city":"Новосибирск","country":"Россия","website":"https://park.example",
+				"datasource":{"raw":{"name":"Центральный парк","phone":"+7 111"}}},
+				"geometry":{"coordinates":[82.92,55.03]}}]}`,
+			want: categorizedPtr(model.Place{
+				Xid: "p2", Name: "Парк", Lat: 55.03, Lon: 82.92,
+				Description: "Address: Парк, Новосибирск, Россия\nPhone: +7 111",
+				WebSite:     "https://park.example",
+				Units:       model.UnitsMetric,
+			}),
+		},
+		{
+			name:   "empty name and missing coordinates",
+			status: http.StatusOK,
+			body:   `{"features":[{"properties":{"place_id":"p3"}}]}`,
+			want:   categorizedPtr(model.Place{Xid: "p3", Units: model.UnitsMetric}),
+		},
+		{
+			name:    "no features",
+			status:  http.StatusOK,
+			body:    `{"features":[]}`,
+			wantErr: true,
+		},
+		{
+			name:    "non-200",
+			status:  http.StatusNotFound,
+			body:    `{"error":"Not Found"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.status, tt.body)
+
+			got, err := client.GetPlaceDetails(context.Background(), "p1", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPlaceDetails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPlaceDetails() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPlacesRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/v2/places" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if query.Get("filter") != "circle:83.090000,54.840000,2000" || query.Get("apiKey") != "secret" {
+			t.Errorf("unexpected query %v", query)
+		}
+		if query.Get("lang") != "de" {
+			t.Errorf("lang = %q", query.Get("lang"))
+		}
+		_, _ = w.Write([]byte(`{"features":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret", WithBaseURL(server.URL))
+	if _, err := client.GetPlaces(context.Background(), 54.84, 83.09, 2000, "de"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetPlacesInArea(t *testing.T) {
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("filter"))
+		_, _ = w.Write([]byte(`{"features":[
+			{"properties":{"place_id":"inside","name":"Внутри"},"geometry":{"coordinates":[83.01,54.98]}},
+			{"properties":{"place_id":"corner","name":"В углу"},"geometry":{"coordinates":[83.09,54.99]}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient("secret", WithBaseURL(server.URL))
+
+	bbox := model.BBox{West: 83, South: 54.9, East: 83.1, North: 55}
+	// Треугольник отсекает северо-восточный угол прямоугольника
+	triangle := &model.Polygon{Type: "Polygon", Coordinates: [][][]float64{
+		{{83, 54.9}, {83.1, 54.9}, {83, 55}, {83, 54.9}},
+	}}
+
+	tests := []struct {
+		name   string
+		area   model.Area
+		filter string
+		want   []string
+	}{
+		{"bbox", model.Area{BBox: &bbox}, "rect:83.000000,54.900000,83.100000,55.000000", []string{"inside", "corner"}},
+		{"polygon", model.Area{Polygon: triangle}, "rect:83.000000,54.900000,83.100000,55.000000", []string{"inside"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters = nil
+			places, err := client.GetPlacesInArea(context.Background(), tt.area, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(filters) != 1 || filters[0] != tt.filter {
+				t.Errorf("filters = %v, want %s", filters, tt.filter)
+			}
+			var xids []string
+			for _, place := range places {
+				xids = append(xids, place.Xid)
+			}
+			if !reflect.DeepEqual(xids, tt.want) {
+				t.Errorf("xids = %v, want %v", xids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPlacesInAreaPages(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		// Первая страница занята местами в углу прямоугольника вне полигона
+		features := make([]string, 0, placesLimit)
+		if offset == "" {
+			for range placesLimit {
+				features = append(features, `{"properties":{"place_id":"corner","name":"В углу"},"geometry":{"coordinates":[83.09,54.99]}}`)
+			}
+		} else {
+			features = append(features, `{"properties":{"place_id":"inside","name":"Внутри"},"geometry":{"coordinates":[83.01,54.98]}}`)
+		}
+		_, _ = w.Write([]byte(`{"features":[` + strings.Join(features, ",") + `]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient("secret", WithBaseURL(server.URL))
+
+	triangle := &model.Polygon{Type: "Polygon", Coordinates: [][][]float64{
+		{{83, 54.9}, {83.1, 54.9}, {83, 55}, {83, 54.9}},
+	}}
+	places, err := client.GetPlacesInArea(context.Background(), model.Area{Polygon: triangle}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offsets, []string{"", "50"}) {
+		t.Errorf("offsets = %q, want first page and offset 50", offsets)
+	}
+	if len(places) != 1 || places[0].Xid != "inside" {
+		t.Errorf("places = %+v, want the place from the second page", places)
+	}
+}
+
+func TestContract(t *testing.T) {
+	server := httptest.NewServer(mockserver.New(mockserver.Config{}))
+	t.Cleanup(server.Close)
+
+	servicetest.TestPlacesClient(t, NewClient("key", WithBaseURL(server.URL)), 54.84, 83.09, 2000)
+}
+
+func newTestClient(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("key", WithBaseURL(server.URL))
+}
+
+// categorized заполняет категории так, как их строит адаптер; без ids — CategoryOther
+func categorized(place model.Place, ids ...string) model.Place {
+	place.Categories, place.PrimaryCategory = model.CategoryRefs(ids, "")
+	return place
+}
+
+func categorizedPtr(place model.Place, ids ...string) *model.Place {
+	place = categorized(place, ids...)
+	return &place
+}
@@ -8,18 +8,46 @@ import (
 	"net/http"
 	"net/url"
 	"places/internal/model"
+	"strconv"
+	"strings"
 )
 
+// DefaultBaseURL — адрес API по умолчанию
+const DefaultBaseURL = "https://graphhopper.com"
+
 type Client struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+// Option настраивает Client
+type Option func(*Client)
+
+// WithBaseURL направляет запросы на другой сервер, например на places-mock
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport подменяет HTTP-транспорт, например на запись и воспроизведение ответов
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
 		httpClient: &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 type graphHopperResponse struct {
@@ -35,13 +63,46 @@ type graphHopperResponse struct {
 	} `json:"hits"`
 }
 
-func (c *Client) GetLocations(ctx context.Context, query string) ([]model.Location, error) {
-	baseURL := "https://graphhopper.com/api/1/geocode"
+func (c *Client) GetLocations(ctx context.Context, query, lang string) ([]model.Location, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("limit", "10")
+	return c.geocode(ctx, params, lang)
+}
 
+// Autocomplete ищет локации по началу названия в режиме подсказок GraphHopper
+func (c *Client) Autocomplete(ctx context.Context, query, lang string, limit int) ([]model.Location, error) {
 	params := url.Values{}
 	params.Add("q", query)
+	params.Add("autocomplete", "true")
+	params.Add("limit", strconv.Itoa(limit))
+	return c.geocode(ctx, params, lang)
+}
+
+// ReverseGeocode находит ближайший к точке объект
+func (c *Client) ReverseGeocode(ctx context.Context, lat, lon float64, lang string) (*model.Location, error) {
+	params := url.Values{}
+	params.Add("reverse", "true")
+	params.Add("point", fmt.Sprintf("%f,%f", lat, lon))
+	params.Add("limit", "1")
+
+	locations, err := c.geocode(ctx, params, lang)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("reverse geocoding %f,%f: %w", lat, lon, model.ErrNotFound)
+	}
+	return &locations[0], nil
+}
+
+func (c *Client) geocode(ctx context.Context, params url.Values, lang string) ([]model.Location, error) {
+	baseURL := c.baseURL + "/api/1/geocode"
+
 	params.Add("key", c.apiKey)
-	params.Add("limit", "10")
+	if lang != "" {
+		params.Add("locale", lang)
+	}
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
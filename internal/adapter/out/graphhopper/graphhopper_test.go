@@ -0,0 +1,160 @@
+package graphhopper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"places/internal/mockserver"
+	"places/internal/model"
+	"places/internal/service/servicetest"
+)
+
+func TestGetLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []model.Location
+		wantErr bool
+	}{
+		{
+			name:   "full hit",
+			status: http.StatusOK,
+			body: `{"hits":[{"point":{"lat":54.84,"lng":83.09},"name":"НГУ",
+				"country":"Россия","state":"Новосибирская область","city":"Новосибирск"}]}`,
+			want: []model.Location{{Name: "НГУ", Lat: 54.84, Lon: 83.09, Country: "Россия", State: "Новосибирская область"}},
+		},
+		{
+			name:   "empty name falls back to city",
+			status: http.StatusOK,
+			body:   `{"hits":[{"point":{"lat":55.03,"lng":82.92},"city":"Новосибирск"}]}`,
+			want:   []model.Location{{Name: "Новосибирск", Lat: 55.03, Lon: 82.92}},
+		},
+		{
+			name:   "empty name and city",
+			status: http.StatusOK,
+			body:   `{"hits":[{"point":{"lat":1,"lng":2}}]}`,
+			want:   []model.Location{{Lat: 1, Lon: 2}},
+		},
+		{
+			name:   "missing coordinates",
+			status: http.StatusOK,
+			body:   `{"hits":[{"name":"Без точки"}]}`,
+			want:   []model.Location{{Name: "Без точки"}},
+		},
+		{
+			name:   "no hits",
+			status: http.StatusOK,
+			body:   `{"hits":[]}`,
+			want:   []model.Location{},
+		},
+		{
+			name:    "non-200",
+			status:  http.StatusUnauthorized,
+			body:    `{"message":"Wrong credentials"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			status:  http.StatusOK,
+			body:    `{"hits":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.status, tt.body)
+
+			got, err := client.GetLocations(context.Background(), "query", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLocations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLocations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLocationsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/1/geocode" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if query.Get("q") != "Цветной проезд" || query.Get("key") != "secret" || query.Get("locale") != "en" {
+			t.Errorf("unexpected query %v", query)
+		}
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret", WithBaseURL(server.URL))
+	if _, err := client.GetLocations(context.Background(), "Цветной проезд", "en"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutocompleteRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("q") != "Новос" || query.Get("autocomplete") != "true" || query.Get("limit") != "7" {
+			t.Errorf("unexpected query %v", query)
+		}
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret", WithBaseURL(server.URL))
+	if _, err := client.Autocomplete(context.Background(), "Новос", "", 7); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("reverse") != "true" || query.Get("point") != "55.771900,37.620600" || query.Has("q") {
+			t.Errorf("unexpected query %v", query)
+		}
+		_, _ = w.Write([]byte(`{"hits":[{"name":"Москва","country":"Россия","point":{"lat":55.7719,"lng":37.6206}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret", WithBaseURL(server.URL))
+	location, err := client.ReverseGeocode(context.Background(), 55.7719, 37.6206, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Name != "Москва" || location.Country != "Россия" {
+		t.Errorf("location = %+v", location)
+	}
+
+	empty := newTestClient(t, http.StatusOK, `{"hits":[]}`)
+	if _, err := empty.ReverseGeocode(context.Background(), 0, 0, ""); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestContract(t *testing.T) {
+	server := httptest.NewServer(mockserver.New(mockserver.Config{}))
+	t.Cleanup(server.Close)
+
+	servicetest.TestGeocodingClient(t, NewClient("key", WithBaseURL(server.URL)), "Новосибирск")
+}
+
+func newTestClient(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("key", WithBaseURL(server.URL))
+}
@@ -7,18 +7,46 @@ import (
 	"io"
 	"net/http"
 	"places/internal/model"
+	"strings"
+	"time"
 )
 
+// DefaultBaseURL — адрес API по умолчанию
+const DefaultBaseURL = "https://api.openweathermap.org"
+
 type Client struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+// Option настраивает Client
+type Option func(*Client)
+
+// WithBaseURL направляет запросы на другой сервер, например на places-mock
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport подменяет HTTP-транспорт, например на запись и воспроизведение ответов
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
 		httpClient: &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 type openWeatherResponse struct {
@@ -34,13 +62,17 @@ type openWeatherResponse struct {
 	Wind struct {
 		Speed float64 `json:"speed"`
 	} `json:"wind"`
+	Timezone int `json:"timezone"`
 }
 
-func (c *Client) GetWeather(ctx context.Context, lat, lon float64) (*model.Weather, error) {
+func (c *Client) GetWeather(ctx context.Context, lat, lon float64, lang string) (*model.Weather, error) {
 	url := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=metric",
-		lat, lon, c.apiKey,
+		"%s/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=metric",
+		c.baseURL, lat, lon, c.apiKey,
 	)
+	if lang != "" {
+		url += "&lang=" + lang
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -72,6 +104,8 @@ func (c *Client) GetWeather(ctx context.Context, lat, lon float64) (*model.Weath
 		FeelsLike: owResp.Main.FeelsLike,
 		Humidity:  owResp.Main.Humidity,
 		WindSpeed: owResp.Wind.Speed,
+		Units:     model.UnitsMetric,
+		Timezone:  owResp.Timezone,
 	}
 
 	if len(owResp.Weather) > 0 {
@@ -81,3 +115,79 @@ func (c *Client) GetWeather(ctx context.Context, lat, lon float64) (*model.Weath
 
 	return weather, nil
 }
+
+type openWeatherForecastResponse struct {
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Humidity  int     `json:"humidity"`
+		} `json:"main"`
+		Weather []struct {
+			Description string `json:"description"`
+			Icon        string `json:"icon"`
+		} `json:"weather"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+	} `json:"list"`
+}
+
+func (c *Client) GetForecast(ctx context.Context, lat, lon float64, lang string) (*model.Forecast, error) {
+	url := fmt.Sprintf(
+		"%s/data/2.5/forecast?lat=%f&lon=%f&appid=%s&units=metric",
+		c.baseURL, lat, lon, c.apiKey,
+	)
+	if lang != "" {
+		url += "&lang=" + lang
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			fmt.Println("Error closing response body:", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("forecast API returned status: %d", resp.StatusCode)
+	}
+
+	var owResp openWeatherForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&owResp); err != nil {
+		return nil, err
+	}
+
+	forecast := &model.Forecast{
+		Items: make([]model.ForecastItem, 0, len(owResp.List)),
+	}
+	for _, entry := range owResp.List {
+		item := model.ForecastItem{
+			Time: time.Unix(entry.Dt, 0).UTC(),
+			Weather: model.Weather{
+				Temp:      entry.Main.Temp,
+				FeelsLike: entry.Main.FeelsLike,
+				Humidity:  entry.Main.Humidity,
+				WindSpeed: entry.Wind.Speed,
+				Units:     model.UnitsMetric,
+			},
+		}
+		if len(entry.Weather) > 0 {
+			item.Description = entry.Weather[0].Description
+			item.Icon = entry.Weather[0].Icon
+		}
+		forecast.Items = append(forecast.Items, item)
+	}
+
+	return forecast, nil
+}
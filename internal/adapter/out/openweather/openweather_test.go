@@ -0,0 +1,113 @@
+package openweather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"places/internal/mockserver"
+	"places/internal/model"
+	"places/internal/service/servicetest"
+)
+
+func TestGetWeather(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *model.Weather
+		wantErr bool
+	}{
+		{
+			name:   "full response",
+			status: http.StatusOK,
+			body: `{"main":{"temp":4.2,"feels_like":1.1,"humidity":71},
+				"weather":[{"description":"облачно","icon":"04d"},{"description":"дымка","icon":"50d"}],
+				"wind":{"speed":3.6},"timezone":25200}`,
+			want: &model.Weather{
+				Temp: 4.2, FeelsLike: 1.1, Description: "облачно", Humidity: 71,
+				WindSpeed: 3.6, Icon: "04d", Units: model.UnitsMetric, Timezone: 25200,
+			},
+		},
+		{
+			name:   "empty weather list",
+			status: http.StatusOK,
+			body:   `{"main":{"temp":-12.5,"feels_like":-18,"humidity":90},"weather":[],"wind":{"speed":0}}`,
+			want:   &model.Weather{Temp: -12.5, FeelsLike: -18, Humidity: 90, Units: model.UnitsMetric},
+		},
+		{
+			name:   "missing fields",
+			status: http.StatusOK,
+			body:   `{}`,
+			want:   &model.Weather{Units: model.UnitsMetric},
+		},
+		{
+			name:    "non-200",
+			status:  http.StatusTooManyRequests,
+			body:    `{"cod":429}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			status:  http.StatusOK,
+			body:    `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.status, tt.body)
+
+			got, err := client.GetWeather(context.Background(), 54.84, 83.09, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWeather() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetWeather() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetWeatherRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/data/2.5/weather" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if query.Get("appid") != "secret" || query.Get("units") != "metric" || query.Get("lang") != "ru" {
+			t.Errorf("unexpected query %v", query)
+		}
+		if query.Get("lat") != "54.840000" || query.Get("lon") != "83.090000" {
+			t.Errorf("unexpected coordinates %s,%s", query.Get("lat"), query.Get("lon"))
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("secret", WithBaseURL(server.URL))
+	if _, err := client.GetWeather(context.Background(), 54.84, 83.09, "ru"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContract(t *testing.T) {
+	server := httptest.NewServer(mockserver.New(mockserver.Config{}))
+	t.Cleanup(server.Close)
+
+	servicetest.TestWeatherClient(t, NewClient("key", WithBaseURL(server.URL)), 54.84, 83.09)
+}
+
+func newTestClient(t *testing.T, status int, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("key", WithBaseURL(server.URL))
+}
@@ -0,0 +1,34 @@
+// Package usage учитывает запросы к провайдерам на ключ API из контекста запроса.
+// Транспорт подключается к адаптеру через http.RoundTripper, поэтому учитывается
+// каждый HTTP-запрос, даже если один вызов клиента делает их несколько
+package usage
+
+import (
+	"context"
+	"net/http"
+)
+
+// Recorder учитывает одно обращение к провайдеру
+type Recorder interface {
+	RecordProviderCall(ctx context.Context, provider string)
+}
+
+// Transport учитывает запросы под именем провайдера и передаёт их next
+type Transport struct {
+	next     http.RoundTripper
+	recorder Recorder
+	provider string
+}
+
+// NewTransport создаёт учитывающий транспорт; nil next — http.DefaultTransport
+func NewTransport(next http.RoundTripper, recorder Recorder, provider string) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{next: next, recorder: recorder, provider: provider}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.recorder.RecordProviderCall(req.Context(), t.provider)
+	return t.next.RoundTrip(req)
+}
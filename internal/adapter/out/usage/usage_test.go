@@ -0,0 +1,47 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type keyID struct{}
+
+// countRecorder считает обращения по ключу из контекста
+type countRecorder map[string]int
+
+func (c countRecorder) RecordProviderCall(ctx context.Context, provider string) {
+	key, _ := ctx.Value(keyID{}).(string)
+	c[key+"|"+provider]++
+}
+
+func TestTransportCountsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	recorder := countRecorder{}
+	client := &http.Client{Transport: NewTransport(nil, recorder, "geoapify")}
+	ctx := context.WithValue(context.Background(), keyID{}, "k1")
+
+	// Каждый запрос учитывается, в том числе отклонённый провайдером
+	for _, path := range []string{"/places", "/places?offset=20", "/fail"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if recorder["k1|geoapify"] != 3 || len(recorder) != 1 {
+		t.Errorf("calls = %v, want 3 for k1", recorder)
+	}
+}
@@ -0,0 +1,203 @@
+// Package wikipedia получает начало статьи Википедии о месте по тегу OpenStreetMap wikipedia
+package wikipedia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"places/internal/model"
+	"regexp"
+	"strings"
+)
+
+// DefaultBaseURL — адрес API по умолчанию; {lang} заменяется языком статьи
+const DefaultBaseURL = "https://{lang}.wikipedia.org"
+
+// userAgentName — название клиента в User-Agent. Правила Wikimedia требуют
+// в User-Agent контакт владельца, запросы без него могут отклоняться
+const userAgentName = "places-service/1.0"
+
+// languagePattern — код языкового раздела: "ru", "be-tarask", "zh-min-nan"
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]+)*$`)
+
+type Client struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// Option настраивает Client
+type Option func(*Client)
+
+// WithBaseURL направляет запросы на другой сервер, например на places-mock.
+// Без {lang} в адресе все разделы запрашиваются с одного сервера
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport подменяет HTTP-транспорт, например на запись и воспроизведение ответов
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// NewClient создает клиент. contact — адрес почты или страница владельца сервиса:
+// Wikimedia связывается по нему, если запросы мешают работе API
+func NewClient(contact string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		userAgent:  fmt.Sprintf("%s (%s)", userAgentName, contact),
+		httpClient: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type summaryResponse struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Extract   string `json:"extract"`
+	Thumbnail struct {
+		Source string `json:"source"`
+	} `json:"thumbnail"`
+	OriginalImage struct {
+		Source string `json:"source"`
+	} `json:"originalimage"`
+	ContentURLs struct {
+		Desktop struct {
+			Page string `json:"page"`
+		} `json:"desktop"`
+	} `json:"content_urls"`
+}
+
+// langlinksResponse — ответ Action API на prop=langlinks с formatversion=2
+type langlinksResponse struct {
+	Query struct {
+		Pages []struct {
+			Langlinks []struct {
+				Lang  string `json:"lang"`
+				Title string `json:"title"`
+			} `json:"langlinks"`
+		} `json:"pages"`
+	} `json:"query"`
+}
+
+// GetSummary возвращает начало статьи по тегу. Если раздел тега не совпадает с lang,
+// статья ищется по межъязыковой ссылке; без версии на lang — model.ErrNotFound
+func (c *Client) GetSummary(ctx context.Context, article, lang string) (*model.Summary, error) {
+	tagLang, title, ok := strings.Cut(article, ":")
+	title = strings.TrimSpace(title)
+	if !ok || !languagePattern.MatchString(tagLang) || title == "" {
+		return nil, fmt.Errorf("invalid wikipedia tag %q", article)
+	}
+
+	// Раздел Википедии определяется основным подтегом языка: "en-US" — en.wikipedia.org
+	lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+	if lang != "" && lang != tagLang {
+		if !languagePattern.MatchString(lang) {
+			return nil, fmt.Errorf("invalid wikipedia language %q", lang)
+		}
+		linked, err := c.langlink(ctx, tagLang, title, lang)
+		if err != nil {
+			return nil, err
+		}
+		tagLang, title = lang, linked
+	}
+	return c.summary(ctx, tagLang, title)
+}
+
+// langlink возвращает название статьи title из раздела from в разделе to
+func (c *Client) langlink(ctx context.Context, from, title, to string) (string, error) {
+	params := url.Values{}
+	params.Set("action", "query")
+	params.Set("format", "json")
+	params.Set("formatversion", "2")
+	params.Set("prop", "langlinks")
+	params.Set("redirects", "1")
+	params.Set("titles", title)
+	params.Set("lllang", to)
+	fullURL := fmt.Sprintf("%s/w/api.php?%s", strings.ReplaceAll(c.baseURL, "{lang}", from), params.Encode())
+
+	var lResp langlinksResponse
+	if err := c.get(ctx, fullURL, &lResp); err != nil {
+		return "", err
+	}
+	for _, page := range lResp.Query.Pages {
+		for _, link := range page.Langlinks {
+			if link.Lang == to && link.Title != "" {
+				return link.Title, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("wikipedia article %s:%s has no %s version: %w", from, title, to, model.ErrNotFound)
+}
+
+func (c *Client) summary(ctx context.Context, lang, title string) (*model.Summary, error) {
+	// В адресах статей пробелы заменяются подчёркиваниями
+	fullURL := fmt.Sprintf("%s/api/rest_v1/page/summary/%s",
+		strings.ReplaceAll(c.baseURL, "{lang}", lang),
+		url.PathEscape(strings.ReplaceAll(title, " ", "_")),
+	)
+
+	var sResp summaryResponse
+	if err := c.get(ctx, fullURL, &sResp); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, fmt.Errorf("wikipedia article %s:%s: %w", lang, title, model.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	// Страница разрешения неоднозначностей не рассказывает о месте
+	if sResp.Type == "disambiguation" || sResp.Extract == "" {
+		return nil, fmt.Errorf("wikipedia article %s:%s has no summary: %w", lang, title, model.ErrNotFound)
+	}
+
+	// Миниатюра подходит для карточки места, оригинал может весить мегабайты
+	image := sResp.Thumbnail.Source
+	if image == "" {
+		image = sResp.OriginalImage.Source
+	}
+
+	return &model.Summary{
+		Title:   sResp.Title,
+		Extract: sResp.Extract,
+		Image:   image,
+		URL:     sResp.ContentURLs.Desktop.Page,
+	}, nil
+}
+
+// get выполняет GET-запрос и разбирает JSON-ответ в v; 404 — model.ErrNotFound
+func (c *Client) get(ctx context.Context, fullURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wikipedia API returned status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
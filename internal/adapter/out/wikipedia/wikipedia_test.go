@@ -0,0 +1,185 @@
+package wikipedia
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"places/internal/adapter/out/usage"
+	"places/internal/mockserver"
+	"places/internal/model"
+	"places/internal/service/servicetest"
+)
+
+func TestGetSummary(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		want         *model.Summary
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name:   "standard article",
+			status: http.StatusOK,
+			body: `{"type":"standard","title":"Большой театр","extract":"Один из крупнейших театров.",
+				"thumbnail":{"source":"https://img.example/320px.jpg"},"originalimage":{"source":"https://img.example/full.jpg"},
+				"content_urls":{"desktop":{"page":"https://ru.wikipedia.org/wiki/Большой_театр"}}}`,
+			want: &model.Summary{
+				Title: "Большой театр", Extract: "Один из крупнейших театров.",
+				Image: "https://img.example/320px.jpg", URL: "https://ru.wikipedia.org/wiki/Большой_театр",
+			},
+		},
+		{
+			name:   "original image without thumbnail",
+			status: http.StatusOK,
+			body:   `{"type":"standard","title":"Театр","extract":"Театр.","originalimage":{"source":"https://img.example/full.jpg"}}`,
+			want:   &model.Summary{Title: "Театр", Extract: "Театр.", Image: "https://img.example/full.jpg"},
+		},
+		{
+			name:         "disambiguation",
+			status:       http.StatusOK,
+			body:         `{"type":"disambiguation","title":"Театр","extract":"Театр может означать:"}`,
+			wantNotFound: true,
+		},
+		{
+			name:         "not found",
+			status:       http.StatusNotFound,
+			body:         `{"type":"https://mediawiki.org/wiki/HyperSwitch/errors/not_found"}`,
+			wantNotFound: true,
+		},
+		{
+			name:    "non-200",
+			status:  http.StatusInternalServerError,
+			body:    `{}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			got, err := NewClient("ops@example.org", WithBaseURL(server.URL)).GetSummary(context.Background(), "ru:Большой театр", "")
+			if tt.wantNotFound {
+				if !errors.Is(err, model.ErrNotFound) {
+					t.Fatalf("GetSummary() error = %v, want model.ErrNotFound", err)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "ru.wiki.test" {
+			t.Errorf("host = %s", r.Host)
+		}
+		if r.URL.EscapedPath() != "/api/rest_v1/page/summary/%D0%91%D0%BE%D0%BB%D1%8C%D1%88%D0%BE%D0%B9_%D1%82%D0%B5%D0%B0%D1%82%D1%80_%28%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0%29" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		if ua := r.Header.Get("User-Agent"); !strings.Contains(ua, "ops@example.org") {
+			t.Errorf("User-Agent = %q, want the contact", ua)
+		}
+		_, _ = w.Write([]byte(`{"type":"standard","title":"Большой театр","extract":"Театр."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	// Язык статьи подставляется в адрес; запрос уходит на тестовый сервер через прокси
+	proxy := &http.Transport{Proxy: func(*http.Request) (*url.URL, error) { return url.Parse(server.URL) }}
+	client := NewClient("ops@example.org", WithBaseURL("http://{lang}.wiki.test"), WithTransport(proxy))
+	if _, err := client.GetSummary(context.Background(), "ru:Большой театр (Москва)", "ru"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tag := range []string{"Большой театр", "ru:", "https://ru.wikipedia.org/wiki/Театр"} {
+		if _, err := client.GetSummary(context.Background(), tag, ""); err == nil {
+			t.Errorf("GetSummary(%q): expected error", tag)
+		}
+	}
+}
+
+// callCounter считает учтённые обращения к провайдерам
+type callCounter map[string]int
+
+func (c callCounter) RecordProviderCall(_ context.Context, provider string) {
+	c[provider]++
+}
+
+func TestSummaryLanguage(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Host+" "+r.URL.Path)
+		if r.URL.Path == "/w/api.php" {
+			q := r.URL.Query()
+			if q.Get("titles") != "Большой театр" || q.Get("prop") != "langlinks" {
+				t.Errorf("query = %s", r.URL.RawQuery)
+			}
+			if q.Get("lllang") != "en" {
+				_, _ = w.Write([]byte(`{"query":{"pages":[{"title":"Большой театр"}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"query":{"pages":[{"title":"Большой театр","langlinks":[{"lang":"en","title":"Bolshoi Theatre"}]}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"type":"standard","title":"Bolshoi Theatre","extract":"A theatre in Moscow."}`))
+	}))
+	t.Cleanup(server.Close)
+	proxy := &http.Transport{Proxy: func(*http.Request) (*url.URL, error) { return url.Parse(server.URL) }}
+	calls := callCounter{}
+	client := NewClient("ops@example.org", WithBaseURL("http://{lang}.wiki.test"),
+		WithTransport(usage.NewTransport(proxy, calls, "wikipedia")))
+
+	// Статья на языке запроса ищется по межъязыковой ссылке из раздела тега
+	summary, err := client.GetSummary(context.Background(), "ru:Большой театр", "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Extract != "A theatre in Moscow." {
+		t.Errorf("summary = %+v", summary)
+	}
+	want := []string{"ru.wiki.test /w/api.php", "en.wiki.test /api/rest_v1/page/summary/Bolshoi_Theatre"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+	// Межъязыковая ссылка и статья — два учтённых запроса
+	if calls["wikipedia"] != 2 {
+		t.Errorf("recorded calls = %v, want 2", calls)
+	}
+
+	// Без версии на языке запроса статья на чужом языке не отдаётся
+	if _, err := client.GetSummary(context.Background(), "ru:Большой театр", "de"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("missing language: error = %v, want model.ErrNotFound", err)
+	}
+}
+
+func TestContract(t *testing.T) {
+	server := httptest.NewServer(mockserver.New(mockserver.Config{}))
+	t.Cleanup(server.Close)
+
+	servicetest.TestSummaryClient(t, NewClient("ops@example.org", WithBaseURL(server.URL)), "ru:Музей истории и культуры народов Сибири и Дальнего Востока")
+
+	// Фикстуры places-mock содержат и английскую версию статьи
+	summary, err := NewClient("ops@example.org", WithBaseURL(server.URL)).
+		GetSummary(context.Background(), "ru:Музей истории и культуры народов Сибири и Дальнего Востока", "en")
+	if err != nil || !strings.HasPrefix(summary.Extract, "The Museum") {
+		t.Errorf("english summary = %+v, err = %v", summary, err)
+	}
+}
@@ -2,21 +2,47 @@ package app
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	placesv1 "places/api/places/v1"
 	"places/internal/adapter/in"
+	"places/internal/adapter/in/gql"
+	"places/internal/adapter/in/rpc"
+	"places/internal/adapter/out/boltstore"
+	"places/internal/adapter/out/fixture"
 	"places/internal/adapter/out/geoapify"
 	"places/internal/adapter/out/graphhopper"
 	"places/internal/adapter/out/openweather"
+	"places/internal/adapter/out/usage"
+	"places/internal/adapter/out/wikipedia"
 	"places/internal/service"
 	"places/internal/util"
 )
 
+// detailsCost — стоимость запроса деталей одной локации в токенах ограничителя
+const detailsCost = 10
+
 type App struct {
-	router  *mux.Router
-	handler *in.Handler
+	router           *mux.Router
+	handler          *in.Handler
+	batchHandler     *in.BatchHandler
+	autocomplete     *in.AutocompleteHandler
+	favoritesHandler *in.FavoritesHandler
+	historyHandler   *in.HistoryHandler
+	apiKeyHandler    *in.APIKeyHandler
+	graphqlHandler   http.Handler
+	grpcServer       *grpc.Server
+	spec             *openapi3.T
+	validator        mux.MiddlewareFunc
+	rateLimiter      *in.RateLimiter
+	requireAPIKey    bool
+	adminToken       string
 }
 
 func NewApp() *App {
@@ -27,17 +53,88 @@ func NewApp() *App {
 	openWeatherKey := os.Getenv("OPENWEATHER_API_KEY")
 	geoapifyKey := os.Getenv("GEOAPIFY_API_KEY")
 
-	if graphHopperKey == "" || openWeatherKey == "" || geoapifyKey == "" {
+	// Запись и воспроизведение ответов провайдеров для работы без сети
+	fixtureMode := fixture.Mode(os.Getenv("HTTP_FIXTURES_MODE"))
+	transport, err := fixture.NewTransport(fixtureMode, util.GetEnv("HTTP_FIXTURES_DIR", "testdata/fixtures"), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// При воспроизведении ключи не нужны: записи хранятся без них
+	if fixtureMode != fixture.ModeReplay && (graphHopperKey == "" || openWeatherKey == "" || geoapifyKey == "") {
 		log.Fatal("API keys must be set in environment variables")
 	}
 
-	// Создаем клиенты
-	geocodingClient := graphhopper.NewClient(graphHopperKey)
-	weatherClient := openweather.NewClient(openWeatherKey)
-	placesClient := geoapify.NewClient(geoapifyKey)
+	// Встроенное файловое хранилище для пользовательских данных
+	db, err := boltstore.Open(util.GetEnv("DB_PATH", "places.db"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	favoritesStore, err := boltstore.NewFavoritesStore(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	historyStore, err := boltstore.NewHistoryStore(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	apiKeyStore, err := boltstore.NewAPIKeyStore(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	placesIndex, err := boltstore.NewPlacesIndexStore(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	apiKeySrv := service.NewAPIKeyService(apiKeyStore)
+
+	// Создаем клиенты; каждый HTTP-запрос к провайдеру учитывается на ключ API из запроса
+	// Адреса провайдеров можно переопределить, например на places-mock
+	geocodingClient := graphhopper.NewClient(graphHopperKey,
+		graphhopper.WithBaseURL(util.GetEnv("GRAPHHOPPER_BASE_URL", graphhopper.DefaultBaseURL)),
+		graphhopper.WithTransport(usage.NewTransport(transport, apiKeySrv, "graphhopper")),
+	)
+	weatherClient := openweather.NewClient(openWeatherKey,
+		openweather.WithBaseURL(util.GetEnv("OPENWEATHER_BASE_URL", openweather.DefaultBaseURL)),
+		openweather.WithTransport(usage.NewTransport(transport, apiKeySrv, "openweather")),
+	)
+	// Индекс снаружи учёта: ответы из него не расходуют квоту провайдера
+	placesClient := service.IndexPlaces(
+		geoapify.NewClient(geoapifyKey,
+			geoapify.WithBaseURL(util.GetEnv("GEOAPIFY_BASE_URL", geoapify.DefaultBaseURL)),
+			geoapify.WithTransport(usage.NewTransport(transport, apiKeySrv, "geoapify")),
+		),
+		placesIndex, util.GetEnvDuration("PLACES_INDEX_TTL", 24*time.Hour))
+	// Wikimedia требует контакт владельца в User-Agent, без него статьи не запрашиваются.
+	// Кеш снаружи учёта: повторные статьи не расходуют запросы к Википедии
+	var summaryClient service.SummaryClient
+	if contact := os.Getenv("WIKIPEDIA_CONTACT"); contact != "" {
+		summaryClient = service.CacheSummaries(
+			wikipedia.NewClient(contact,
+				wikipedia.WithBaseURL(util.GetEnv("WIKIPEDIA_BASE_URL", wikipedia.DefaultBaseURL)),
+				wikipedia.WithTransport(usage.NewTransport(transport, apiKeySrv, "wikipedia")),
+			),
+			util.GetEnvDuration("WIKIPEDIA_CACHE_TTL", 24*time.Hour))
+	} else {
+		log.Print("WIKIPEDIA_CONTACT is not set: place descriptions will not include Wikipedia summaries")
+	}
 
-	// Создаем сервис
-	srv := service.NewService(geocodingClient, weatherClient, placesClient)
+	// Создаем сервисы
+	srv := service.WithHistory(
+		service.NewService(geocodingClient, weatherClient, placesClient, summaryClient),
+		historyStore,
+	)
+	favoritesSrv := service.NewFavoritesService(favoritesStore)
+	historySrv := service.NewHistoryService(historyStore)
+	// Пакет списывает detailsCost за каждую локацию сразу и должен укладываться в burst
+	rateLimitBurst := util.GetEnvInt("RATE_LIMIT_BURST", 40)
+	batchMaxLocations := util.GetEnvInt("BATCH_MAX_LOCATIONS", rateLimitBurst/detailsCost)
+	if maxByBurst := rateLimitBurst / detailsCost; batchMaxLocations > maxByBurst {
+		log.Printf("BATCH_MAX_LOCATIONS=%d costs more than RATE_LIMIT_BURST=%d allows, using %d",
+			batchMaxLocations, rateLimitBurst, maxByBurst)
+		batchMaxLocations = maxByBurst
+	}
+	batchSrv := service.NewBatchService(srv, util.GetEnvInt("BATCH_CONCURRENCY", 4), batchMaxLocations)
 
 	// Создаем HTTP handler
 	handler := in.NewHandler(srv)
@@ -45,9 +142,65 @@ func NewApp() *App {
 	// Создаем router
 	router := mux.NewRouter()
 
+	// Спецификация OpenAPI: отдаётся клиентам и используется для валидации запросов
+	spec, err := in.NewOpenAPISpec()
+	if err != nil {
+		log.Fatal(err)
+	}
+	validator, err := in.ValidateRequests(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Ограничение частоты запросов с одного IP
+	trustedProxies, err := in.ParseCIDRs(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	rateLimiter := in.NewRateLimiter(
+		util.GetEnvFloat("RATE_LIMIT_RPS", 2),
+		rateLimitBurst,
+		trustedProxies,
+	)
+
+	// Ключ обязателен, пока его явно не отключили через REQUIRE_API_KEY=false
+	requireAPIKey := util.GetEnvBool("REQUIRE_API_KEY", true)
+
+	// Создаем gRPC сервер поверх того же сервиса, с теми же лимитами и ключами API
+	limitUnary, limitStream := rpc.RateLimit(rateLimiter, map[string]int{
+		placesv1.PlacesService_SearchLocations_FullMethodName:       1,
+		placesv1.PlacesService_GetLocationDetails_FullMethodName:    detailsCost,
+		placesv1.PlacesService_StreamLocationDetails_FullMethodName: detailsCost,
+	})
+	unary := []grpc.UnaryServerInterceptor{limitUnary}
+	stream := []grpc.StreamServerInterceptor{limitStream}
+	// Лимит проверяется первым, чтобы отклонённые вызовы не тратили квоту ключа
+	if requireAPIKey {
+		authUnary, authStream := rpc.RequireAPIKey(apiKeySrv)
+		unary = append(unary, authUnary)
+		stream = append(stream, authStream)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	placesv1.RegisterPlacesServiceServer(grpcServer, rpc.NewServer(srv))
+
 	app := &App{
-		router:  router,
-		handler: handler,
+		router:           router,
+		handler:          handler,
+		batchHandler:     in.NewBatchHandler(batchSrv, rateLimiter, detailsCost, batchMaxLocations),
+		autocomplete:     in.NewAutocompleteHandler(srv, rateLimiter.ClientIP),
+		favoritesHandler: in.NewFavoritesHandler(favoritesSrv),
+		historyHandler:   in.NewHistoryHandler(historySrv),
+		apiKeyHandler:    in.NewAPIKeyHandler(apiKeySrv),
+		graphqlHandler:   gql.NewHandler(srv),
+		grpcServer:       grpcServer,
+		spec:             spec,
+		validator:        validator,
+		rateLimiter:      rateLimiter,
+		requireAPIKey:    requireAPIKey,
+		adminToken:       os.Getenv("ADMIN_TOKEN"),
 	}
 
 	app.setupRoutes()
@@ -56,8 +209,80 @@ func NewApp() *App {
 
 func (a *App) setupRoutes() {
 	// API routes
-	a.router.HandleFunc("/api/search", a.handler.SearchLocations).Methods("POST")
-	a.router.HandleFunc("/api/location/details", a.handler.GetLocationDetails).Methods("POST")
+	api := a.router.PathPrefix("/api").Subrouter()
+	api.Use(in.Language, in.Units, a.validator)
+	api.HandleFunc("/openapi.json", in.OpenAPIHandler(a.spec)).Methods("GET")
+	api.HandleFunc("/categories", in.Categories).Methods("GET")
+
+	// Управление ключами API доступно только с административным токеном
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(in.RequireAdmin(a.adminToken))
+	admin.HandleFunc("/keys", a.apiKeyHandler.Keys).Methods("GET")
+	admin.HandleFunc("/keys", a.apiKeyHandler.CreateKey).Methods("POST")
+	admin.HandleFunc("/keys/usage", a.apiKeyHandler.Usage).Methods("GET")
+	admin.HandleFunc("/keys/{id}", a.apiKeyHandler.UpdateKey).Methods("PATCH")
+	admin.HandleFunc("/keys/{id}", a.apiKeyHandler.DeleteKey).Methods("DELETE")
+	// Статистика собрана по запросам всех пользователей
+	admin.HandleFunc("/stats/popular", a.historyHandler.Popular).Methods("GET")
+
+	// Остальные маршруты ограничены по частоте с одного IP и, если не задано REQUIRE_API_KEY=false,
+	// требуют ключ API. Лимит проверяется первым, чтобы отклонённые запросы не тратили квоту
+	protected := api.NewRoute().Subrouter()
+	// Детали порождают десятки обращений к провайдерам, поэтому стоят дороже поиска,
+	// а поиск в полигоне — до PolygonPages страниц мест
+	protected.Use(a.rateLimiter.Limit(map[string]int{
+		"search":               1,
+		"searchByQuery":        1,
+		"autocomplete":         1,
+		"details":              detailsCost,
+		"detailsByCoordinates": detailsCost,
+		"detailsGeoJSON":       detailsCost,
+		"detailsGPX":           detailsCost,
+		"detailsKML":           detailsCost,
+		"placesInViewport":     1,
+		"placesInArea":         geoapify.PolygonPages,
+	}))
+	// Запрос GraphQL может запросить детали, поэтому стоит как они,
+	// а бюджет обращений к сервису не даёт ему получить больше
+	graphql := a.router.Path("/graphql").Subrouter()
+	graphql.Use(in.Language, in.Units, a.rateLimiter.Limit(map[string]int{"graphql": detailsCost}))
+	if a.requireAPIKey {
+		protected.Use(a.apiKeyHandler.RequireAPIKey)
+		graphql.Use(a.apiKeyHandler.RequireAPIKey)
+	} else {
+		protected.Use(a.apiKeyHandler.OptionalAPIKey)
+		graphql.Use(a.apiKeyHandler.OptionalAPIKey)
+	}
+	// Пользователь определяется после ключа, к которому он привязан
+	protected.Use(in.UserContext)
+	graphql.Use(in.UserContext)
+	graphql.Handle("", a.graphqlHandler).Methods("POST").Name("graphql")
+
+	protected.HandleFunc("/search", a.handler.SearchLocations).Methods("POST").Name("search")
+	protected.HandleFunc("/location/details", a.handler.GetLocationDetails).Methods("POST").Name("details")
+	protected.HandleFunc("/search", a.handler.SearchLocationsByQuery).Methods("GET").Name("searchByQuery")
+	protected.HandleFunc("/autocomplete", a.autocomplete.Autocomplete).Methods("GET").Name("autocomplete")
+	protected.HandleFunc("/locations/{lat},{lon}/details", a.handler.GetLocationDetailsByCoordinates).Methods("GET").Name("detailsByCoordinates")
+	protected.HandleFunc("/locations/{lat},{lon}/details.geojson", a.handler.GetLocationDetailsGeoJSON).Methods("GET").Name("detailsGeoJSON")
+	protected.HandleFunc("/locations/{lat},{lon}/details.gpx", a.handler.GetLocationDetailsGPX).Methods("GET").Name("detailsGPX")
+	protected.HandleFunc("/locations/{lat},{lon}/details.kml", a.handler.GetLocationDetailsKML).Methods("GET").Name("detailsKML")
+	protected.HandleFunc("/places", a.handler.GetPlacesInViewport).Methods("GET").Name("placesInViewport")
+	protected.HandleFunc("/places:search", a.handler.SearchPlacesInArea).Methods("POST").Name("placesInArea")
+	// Стоимость пакета зависит от числа локаций и списывается в обработчике
+	protected.HandleFunc("/locations/details:batch", a.batchHandler.GetLocationDetailsBatch).Methods("POST")
+
+	// Избранное
+	protected.HandleFunc("/favorites", a.favoritesHandler.Lists).Methods("GET")
+	protected.HandleFunc("/favorites/{list}", a.favoritesHandler.List).Methods("GET")
+	protected.HandleFunc("/favorites/{list}", a.favoritesHandler.RenameList).Methods("PATCH")
+	protected.HandleFunc("/favorites/{list}", a.favoritesHandler.DeleteList).Methods("DELETE")
+	protected.HandleFunc("/favorites/{list}/locations", a.favoritesHandler.AddLocation).Methods("POST")
+	protected.HandleFunc("/favorites/{list}/locations/{lat},{lon}", a.favoritesHandler.RemoveLocation).Methods("DELETE")
+	protected.HandleFunc("/favorites/{list}/places", a.favoritesHandler.AddPlace).Methods("POST")
+	protected.HandleFunc("/favorites/{list}/places/{xid}", a.favoritesHandler.RemovePlace).Methods("DELETE")
+
+	// История
+	protected.HandleFunc("/history", a.historyHandler.History).Methods("GET")
 
 	// Serve static files
 	staticDir := http.Dir("./web")
@@ -70,3 +295,12 @@ func (a *App) Run(addr string) error {
 	log.Printf("Server starting on %s", addr)
 	return http.ListenAndServe(addr, a.router)
 }
+
+func (a *App) RunGRPC(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("gRPC server starting on %s", addr)
+	return a.grpcServer.Serve(lis)
+}
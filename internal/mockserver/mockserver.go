@@ -0,0 +1,162 @@
+// Package mockserver эмулирует API GraphHopper, OpenWeather и Geoapify по набору фикстур.
+// Используется командой places-mock для локального запуска и нагрузочных тестов
+package mockserver
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// embedded — встроенный набор ответов в формате реальных API
+//
+//go:embed fixtures
+var embedded embed.FS
+
+// DefaultFixtures возвращает встроенный набор фикстур
+func DefaultFixtures() fs.FS {
+	fixtures, err := fs.Sub(embedded, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	return fixtures
+}
+
+// Config описывает фикстуры и вносимые сбои.
+// Доли ошибок задаются числом от 0 до 1 и применяются к каждому запросу
+type Config struct {
+	Fixtures fs.FS
+	// Latency добавляется к каждому ответу, Jitter — случайная добавка сверху
+	Latency time.Duration
+	Jitter  time.Duration
+	// ErrorRate — доля ответов 500
+	ErrorRate float64
+	// ThrottleRate — доля ответов 429 с Retry-After
+	ThrottleRate float64
+	// RetryAfter — значение Retry-After для ответов 429
+	RetryAfter time.Duration
+}
+
+// Файлы набора фикстур. Детали мест ищутся как place-details/<id>.json,
+// статьи Википедии — как wikipedia/<название_статьи>.json, их межъязыковые
+// ссылки — как wikipedia/langlinks/<название статьи>.json
+const (
+	geocodeFixture  = "geocode.json"
+	weatherFixture  = "weather.json"
+	forecastFixture = "forecast.json"
+	placesFixture   = "places.json"
+	detailsDir      = "place-details"
+	wikipediaDir    = "wikipedia"
+)
+
+// New собирает обработчик с эндпоинтами всех провайдеров
+func New(cfg Config) http.Handler {
+	if cfg.Fixtures == nil {
+		cfg.Fixtures = DefaultFixtures()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/1/geocode", serveFixture(cfg.Fixtures, geocodeFixture))
+	mux.Handle("GET /data/2.5/weather", serveFixture(cfg.Fixtures, weatherFixture))
+	mux.Handle("GET /data/2.5/forecast", serveFixture(cfg.Fixtures, forecastFixture))
+	mux.Handle("GET /v2/places", serveFixture(cfg.Fixtures, placesFixture))
+	mux.HandleFunc("GET /v2/place-details", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if id == "" || id != path.Base(id) {
+			http.Error(w, `{"error":"Bad Request","message":"id is required"}`, http.StatusBadRequest)
+			return
+		}
+		serveFixture(cfg.Fixtures, path.Join(detailsDir, id+".json")).ServeHTTP(w, r)
+	})
+	// Все языковые разделы Википедии обслуживаются одним адресом
+	mux.HandleFunc("GET /api/rest_v1/page/summary/{title}", func(w http.ResponseWriter, r *http.Request) {
+		title := r.PathValue("title")
+		if title != path.Base(title) {
+			http.Error(w, `{"type":"https://mediawiki.org/wiki/HyperSwitch/errors/bad_request"}`, http.StatusBadRequest)
+			return
+		}
+		serveFixture(cfg.Fixtures, path.Join(wikipediaDir, title+".json")).ServeHTTP(w, r)
+	})
+	// Action API отвечает 200 и без ссылок, поэтому без фикстуры отдаётся пустая страница
+	mux.HandleFunc("GET /w/api.php", func(w http.ResponseWriter, r *http.Request) {
+		title := r.URL.Query().Get("titles")
+		name := path.Join(wikipediaDir, "langlinks", title+".json")
+		if _, err := fs.Stat(cfg.Fixtures, name); title == "" || title != path.Base(title) || err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"query":{"pages":[{"missing":true}]}}`))
+			return
+		}
+		serveFixture(cfg.Fixtures, name).ServeHTTP(w, r)
+	})
+
+	return injectFaults(cfg, mux)
+}
+
+func serveFixture(fixtures fs.FS, name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := fs.ReadFile(fixtures, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+}
+
+// injectFaults задерживает ответы и случайно отвечает 429 или 500
+func injectFaults(cfg Config, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := sleep(r.Context(), delay(cfg)); err != nil {
+			return
+		}
+
+		switch roll := rand.Float64(); {
+		case roll < cfg.ThrottleRate:
+			retryAfter := max(1, int(cfg.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, `{"error":"Too Many Requests"}`, http.StatusTooManyRequests)
+			log.Printf("%s %s: injected 429", r.Method, r.URL.Path)
+		case roll < cfg.ThrottleRate+cfg.ErrorRate:
+			http.Error(w, `{"error":"Internal Server Error"}`, http.StatusInternalServerError)
+			log.Printf("%s %s: injected 500", r.Method, r.URL.Path)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func delay(cfg Config) time.Duration {
+	d := cfg.Latency
+	if cfg.Jitter > 0 {
+		d += rand.N(cfg.Jitter)
+	}
+	return d
+}
+
+// sleep прерывается, если клиент закрыл соединение
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
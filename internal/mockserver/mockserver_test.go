@@ -0,0 +1,161 @@
+package mockserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var testFixtures = fstest.MapFS{
+	geocodeFixture:                         {Data: []byte(`{"hits":[]}`)},
+	detailsDir + "/N1.json":                {Data: []byte(`{"properties":{"place_id":"N1"}}`)},
+	wikipediaDir + "/Театр.json":           {Data: []byte(`{"title":"Театр"}`)},
+	wikipediaDir + "/langlinks/Театр.json": {Data: []byte(`{"query":{"pages":[{"title":"Театр"}]}}`)},
+}
+
+func serve(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestRoutes(t *testing.T) {
+	handler := New(Config{Fixtures: testFixtures})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		body   string
+	}{
+		{"fixture", http.MethodGet, "/api/1/geocode?q=x", http.StatusOK, `{"hits":[]}`},
+		{"missing fixture", http.MethodGet, "/data/2.5/weather", http.StatusNotFound, ""},
+		{"place details", http.MethodGet, "/v2/place-details?id=N1", http.StatusOK, `{"properties":{"place_id":"N1"}}`},
+		{"unknown place", http.MethodGet, "/v2/place-details?id=N2", http.StatusNotFound, ""},
+		{"empty place id", http.MethodGet, "/v2/place-details?id=", http.StatusBadRequest, ""},
+		{"place id outside the directory", http.MethodGet, "/v2/place-details?id=../geocode", http.StatusBadRequest, ""},
+		{"summary", http.MethodGet, "/api/rest_v1/page/summary/Театр", http.StatusOK, `{"title":"Театр"}`},
+		{"unknown summary", http.MethodGet, "/api/rest_v1/page/summary/Опера", http.StatusNotFound, ""},
+		{"langlinks", http.MethodGet, "/w/api.php?titles=Театр", http.StatusOK, `{"query":{"pages":[{"title":"Театр"}]}}`},
+		{"langlinks without fixture", http.MethodGet, "/w/api.php?titles=Опера", http.StatusOK, `{"query":{"pages":[{"missing":true}]}}`},
+		{"langlinks without title", http.MethodGet, "/w/api.php", http.StatusOK, `{"query":{"pages":[{"missing":true}]}}`},
+		{"unknown route", http.MethodGet, "/v3/places", http.StatusNotFound, ""},
+		{"wrong method", http.MethodPost, "/api/1/geocode", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, tt.method, tt.target)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %s, want %s", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestDefaultFixtures(t *testing.T) {
+	handler := New(Config{})
+	for _, target := range []string{"/api/1/geocode", "/data/2.5/weather", "/data/2.5/forecast", "/v2/places"} {
+		if rec := serve(handler, http.MethodGet, target); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("%s: status %d, Content-Type %q", target, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		status     int
+		retryAfter string
+	}{
+		{"no faults", Config{}, http.StatusOK, ""},
+		{"always throttled", Config{ThrottleRate: 1, RetryAfter: 3 * time.Second}, http.StatusTooManyRequests, "3"},
+		{"retry-after at least a second", Config{ThrottleRate: 1, RetryAfter: 200 * time.Millisecond}, http.StatusTooManyRequests, "1"},
+		{"zero retry-after", Config{ThrottleRate: 1}, http.StatusTooManyRequests, "1"},
+		{"always failing", Config{ErrorRate: 1}, http.StatusInternalServerError, ""},
+		// Доли складываются: 429 проверяется первым
+		{"throttle before error", Config{ThrottleRate: 1, ErrorRate: 1}, http.StatusTooManyRequests, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Fixtures = testFixtures
+			handler := New(tt.cfg)
+			// Доли 0 и 1 дают один и тот же ответ на каждый запрос
+			for range 20 {
+				rec := serve(handler, http.MethodGet, "/api/1/geocode")
+				if rec.Code != tt.status {
+					t.Fatalf("status %d, want %d", rec.Code, tt.status)
+				}
+				if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+					t.Fatalf("Retry-After = %q, want %q", got, tt.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorRateIsSplit(t *testing.T) {
+	handler := New(Config{Fixtures: testFixtures, ThrottleRate: 0.5, ErrorRate: 0.5})
+
+	// Сумма долей 1: успешных ответов нет, встречаются оба сбоя
+	codes := make(map[int]int)
+	for range 200 {
+		codes[serve(handler, http.MethodGet, "/api/1/geocode").Code]++
+	}
+	if codes[http.StatusOK] != 0 || codes[http.StatusTooManyRequests] == 0 || codes[http.StatusInternalServerError] == 0 {
+		t.Errorf("status codes = %v", codes)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	handler := New(Config{Fixtures: testFixtures, Latency: 50 * time.Millisecond, Jitter: 10 * time.Millisecond})
+
+	start := time.Now()
+	if rec := serve(handler, http.MethodGet, "/api/1/geocode"); rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("response after %v, want at least the latency", elapsed)
+	}
+
+	// Клиент ушёл: ожидание прерывается и ответ не пишется
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	start = time.Now()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/1/geocode", nil).WithContext(ctx))
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Errorf("canceled request waited %v", elapsed)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("canceled request got a body: %s", rec.Body)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		min, max time.Duration
+	}{
+		{"zero", Config{}, 0, 0},
+		{"negative jitter is ignored", Config{Latency: time.Second, Jitter: -time.Second}, time.Second, time.Second},
+		{"latency with jitter", Config{Latency: time.Second, Jitter: time.Millisecond}, time.Second, time.Second + time.Millisecond - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				if d := delay(tt.cfg); d < tt.min || d > tt.max {
+					t.Fatalf("delay = %v, want within [%v, %v]", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
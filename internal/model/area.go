@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// BBox — прямоугольная область, например видимая часть карты.
+// Области через антимеридиан не поддерживаются: West всегда меньше East
+type BBox struct {
+	West  float64 `json:"west"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+}
+
+// Polygon — геометрия Polygon из GeoJSON: первое кольцо внешнее, остальные — дыры.
+// Точки в порядке [lon, lat], каждое кольцо замкнуто
+type Polygon struct {
+	Type        string        `json:"type"`
+	Coordinates [][][]float64 `json:"coordinates"`
+}
+
+// MaxAreaSpan — наибольшая высота и ширина области поиска в градусах, около 220 км по широте.
+// Провайдер отдаёт из области ограниченное число мест, а индекс обходит её по ячейкам
+const MaxAreaSpan = 2.0
+
+// Area — область поиска мест: прямоугольник или полигон, ровно одно из двух
+type Area struct {
+	BBox    *BBox    `json:"bbox,omitempty"`
+	Polygon *Polygon `json:"polygon,omitempty"`
+}
+
+// Validate проверяет область; ошибки оборачивают ErrInvalidArea
+func (a Area) Validate() error {
+	switch {
+	case a.BBox == nil && a.Polygon == nil:
+		return fmt.Errorf("%w: bbox or polygon is required", ErrInvalidArea)
+	case a.BBox != nil && a.Polygon != nil:
+		return fmt.Errorf("%w: only one of bbox and polygon is allowed", ErrInvalidArea)
+	case a.BBox != nil:
+		if err := a.BBox.validate(); err != nil {
+			return err
+		}
+	default:
+		if err := a.Polygon.validate(); err != nil {
+			return err
+		}
+	}
+
+	bounds := a.Bounds()
+	if bounds.North-bounds.South > MaxAreaSpan || bounds.East-bounds.West > MaxAreaSpan {
+		return fmt.Errorf("%w: area is larger than %v° on a side", ErrInvalidArea, MaxAreaSpan)
+	}
+	return nil
+}
+
+// Bounds возвращает описанный вокруг области прямоугольник
+func (a Area) Bounds() BBox {
+	if a.BBox != nil {
+		return *a.BBox
+	}
+
+	bounds := BBox{West: math.Inf(1), South: math.Inf(1), East: math.Inf(-1), North: math.Inf(-1)}
+	for _, position := range a.Polygon.outer() {
+		bounds.West = math.Min(bounds.West, position[0])
+		bounds.East = math.Max(bounds.East, position[0])
+		bounds.South = math.Min(bounds.South, position[1])
+		bounds.North = math.Max(bounds.North, position[1])
+	}
+	return bounds
+}
+
+// Contains проверяет, лежит ли точка внутри области
+func (a Area) Contains(lat, lon float64) bool {
+	if a.BBox != nil {
+		return a.BBox.Contains(lat, lon)
+	}
+	return a.Polygon.Contains(lat, lon)
+}
+
+// Contains проверяет, лежит ли точка внутри прямоугольника или на его границе
+func (b BBox) Contains(lat, lon float64) bool {
+	return lat >= b.South && lat <= b.North && lon >= b.West && lon <= b.East
+}
+
+// Contains проверяет, лежит ли точка во внешнем кольце и вне дыр
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p.Coordinates) == 0 || !ringContains(p.Coordinates[0], lat, lon) {
+		return false
+	}
+	for _, hole := range p.Coordinates[1:] {
+		if ringContains(hole, lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Polygon) outer() [][]float64 {
+	if len(p.Coordinates) == 0 {
+		return nil
+	}
+	return p.Coordinates[0]
+}
+
+func (b BBox) validate() error {
+	if !validLat(b.South) || !validLat(b.North) || !validLon(b.West) || !validLon(b.East) {
+		return fmt.Errorf("%w: bbox is out of range", ErrInvalidArea)
+	}
+	if b.South >= b.North || b.West >= b.East {
+		return fmt.Errorf("%w: bbox must have south < north and west < east", ErrInvalidArea)
+	}
+	return nil
+}
+
+func (p Polygon) validate() error {
+	if p.Type != "Polygon" {
+		return fmt.Errorf("%w: polygon type must be Polygon", ErrInvalidArea)
+	}
+	if len(p.Coordinates) == 0 {
+		return fmt.Errorf("%w: polygon has no rings", ErrInvalidArea)
+	}
+	for i, ring := range p.Coordinates {
+		// Замкнутый треугольник — минимум четыре точки
+		if len(ring) < 4 {
+			return fmt.Errorf("%w: ring %d has fewer than 4 positions", ErrInvalidArea, i)
+		}
+		for _, position := range ring {
+			if len(position) < 2 || !validLon(position[0]) || !validLat(position[1]) {
+				return fmt.Errorf("%w: ring %d has an invalid position", ErrInvalidArea, i)
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return fmt.Errorf("%w: ring %d is not closed", ErrInvalidArea, i)
+		}
+	}
+	return nil
+}
+
+// ringContains — проверка трассировкой луча; на малых площадях координаты можно считать плоскими
+func ringContains(ring [][]float64, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func validLat(lat float64) bool {
+	return lat >= -90 && lat <= 90
+}
+
+func validLon(lon float64) bool {
+	return lon >= -180 && lon <= 180
+}
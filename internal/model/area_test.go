@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAreaValidate(t *testing.T) {
+	bbox := &BBox{West: 83, South: 54.9, East: 83.1, North: 55}
+	square := [][]float64{{83, 54.9}, {83.1, 54.9}, {83.1, 55}, {83, 55}, {83, 54.9}}
+
+	tests := []struct {
+		name    string
+		area    Area
+		wantErr bool
+	}{
+		{"bbox", Area{BBox: bbox}, false},
+		{"polygon", Area{Polygon: &Polygon{Type: "Polygon", Coordinates: [][][]float64{square}}}, false},
+		{"empty", Area{}, true},
+		{"both", Area{BBox: bbox, Polygon: &Polygon{Type: "Polygon", Coordinates: [][][]float64{square}}}, true},
+		{"inverted bbox", Area{BBox: &BBox{West: 83.1, South: 54.9, East: 83, North: 55}}, true},
+		{"bbox out of range", Area{BBox: &BBox{West: 83, South: 54.9, East: 183, North: 55}}, true},
+		{"bbox too large", Area{BBox: &BBox{West: -180, South: -90, East: 180, North: 90}}, true},
+		{"polygon too large", Area{Polygon: &Polygon{Type: "Polygon", Coordinates: [][][]float64{{{80, 54}, {83.1, 54}, {83.1, 55}, {80, 54}}}}}, true},
+		{"wrong type", Area{Polygon: &Polygon{Type: "MultiPolygon", Coordinates: [][][]float64{square}}}, true},
+		{"open ring", Area{Polygon: &Polygon{Type: "Polygon", Coordinates: [][][]float64{square[:4]}}}, true},
+		{"short ring", Area{Polygon: &Polygon{Type: "Polygon", Coordinates: [][][]float64{{{83, 54.9}, {83.1, 54.9}, {83, 54.9}}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.area.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidArea) {
+				t.Errorf("error %v does not wrap ErrInvalidArea", err)
+			}
+		})
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	// Квадрат 83..83.1 × 54.9..55 с дырой в центре
+	polygon := Polygon{Type: "Polygon", Coordinates: [][][]float64{
+		{{83, 54.9}, {83.1, 54.9}, {83.1, 55}, {83, 55}, {83, 54.9}},
+		{{83.04, 54.94}, {83.06, 54.94}, {83.06, 54.96}, {83.04, 54.96}, {83.04, 54.94}},
+	}}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     bool
+	}{
+		{"inside", 54.92, 83.02, true},
+		{"in hole", 54.95, 83.05, false},
+		{"outside", 55.05, 83.05, false},
+	}
+	for _, tt := range tests {
+		if got := polygon.Contains(tt.lat, tt.lon); got != tt.want {
+			t.Errorf("%s: Contains(%v, %v) = %v, want %v", tt.name, tt.lat, tt.lon, got, tt.want)
+		}
+	}
+
+	bounds := Area{Polygon: &polygon}.Bounds()
+	if bounds != (BBox{West: 83, South: 54.9, East: 83.1, North: 55}) {
+		t.Errorf("Bounds() = %+v", bounds)
+	}
+}
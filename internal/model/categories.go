@@ -0,0 +1,139 @@
+package model
+
+import "strings"
+
+// Category — категория мест в собственной таксономии, не зависящей от провайдера.
+// ID стабилен и иерархичен: "food.cafe" вложена в "food"
+type Category struct {
+	ID     string
+	Parent string
+	// Labels — названия по базовому языку: "ru", "en"
+	Labels map[string]string
+	// Icon — ключ иконки для клиентов
+	Icon string
+}
+
+// CategoryRef — ссылка на категорию с названием на языке запроса
+type CategoryRef struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Icon  string `json:"icon"`
+}
+
+// CategoryOther — категория для мест, которые не удалось отнести ни к одной другой
+const CategoryOther = "other"
+
+// defaultLabelLanguage — язык названий, если нужного перевода нет
+const defaultLabelLanguage = "en"
+
+var categories = []Category{
+	{ID: "sights", Labels: labels("Достопримечательности", "Sights"), Icon: "landmark"},
+	{ID: "sights.museum", Parent: "sights", Labels: labels("Музеи", "Museums"), Icon: "museum"},
+	{ID: "sights.monument", Parent: "sights", Labels: labels("Памятники", "Monuments"), Icon: "monument"},
+	{ID: "sights.religion", Parent: "sights", Labels: labels("Храмы", "Places of worship"), Icon: "place-of-worship"},
+	{ID: "sights.viewpoint", Parent: "sights", Labels: labels("Смотровые площадки", "Viewpoints"), Icon: "viewpoint"},
+
+	{ID: "culture", Labels: labels("Культура", "Culture"), Icon: "theater"},
+	{ID: "culture.theatre", Parent: "culture", Labels: labels("Театры", "Theatres"), Icon: "theater"},
+	{ID: "culture.gallery", Parent: "culture", Labels: labels("Галереи", "Galleries"), Icon: "gallery"},
+
+	{ID: "entertainment", Labels: labels("Развлечения", "Entertainment"), Icon: "ticket"},
+	{ID: "entertainment.cinema", Parent: "entertainment", Labels: labels("Кинотеатры", "Cinemas"), Icon: "cinema"},
+	{ID: "entertainment.zoo", Parent: "entertainment", Labels: labels("Зоопарки и аквариумы", "Zoos and aquariums"), Icon: "zoo"},
+	{ID: "entertainment.theme_park", Parent: "entertainment", Labels: labels("Парки развлечений", "Theme parks"), Icon: "ferris-wheel"},
+
+	{ID: "food", Labels: labels("Еда", "Food"), Icon: "food"},
+	{ID: "food.restaurant", Parent: "food", Labels: labels("Рестораны", "Restaurants"), Icon: "restaurant"},
+	{ID: "food.cafe", Parent: "food", Labels: labels("Кафе", "Cafes"), Icon: "cafe"},
+	{ID: "food.bar", Parent: "food", Labels: labels("Бары", "Bars"), Icon: "bar"},
+	{ID: "food.fast_food", Parent: "food", Labels: labels("Фастфуд", "Fast food"), Icon: "fast-food"},
+
+	{ID: "lodging", Labels: labels("Жильё", "Lodging"), Icon: "bed"},
+
+	{ID: "shopping", Labels: labels("Магазины", "Shopping"), Icon: "shop"},
+	{ID: "shopping.mall", Parent: "shopping", Labels: labels("Торговые центры", "Shopping malls"), Icon: "mall"},
+
+	{ID: "leisure", Labels: labels("Отдых", "Leisure"), Icon: "bench"},
+	{ID: "leisure.park", Parent: "leisure", Labels: labels("Парки", "Parks"), Icon: "park"},
+
+	{ID: "nature", Labels: labels("Природа", "Nature"), Icon: "tree"},
+	{ID: "sport", Labels: labels("Спорт", "Sport"), Icon: "sport"},
+
+	{ID: CategoryOther, Labels: labels("Другое", "Other"), Icon: "pin"},
+}
+
+var categoriesByID = func() map[string]Category {
+	byID := make(map[string]Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	return byID
+}()
+
+func labels(ru, en string) map[string]string {
+	return map[string]string{"ru": ru, "en": en}
+}
+
+// Categories возвращает всю таксономию: родительские категории идут раньше дочерних
+func Categories() []Category {
+	return append([]Category(nil), categories...)
+}
+
+// CategoryByID ищет категорию по идентификатору
+func CategoryByID(id string) (Category, bool) {
+	c, ok := categoriesByID[id]
+	return c, ok
+}
+
+// Label возвращает название категории на языке lang ("ru", "en-US" и т.п.)
+func (c Category) Label(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	if label, ok := c.Labels[strings.ToLower(base)]; ok {
+		return label
+	}
+	return c.Labels[defaultLabelLanguage]
+}
+
+// Ref возвращает ссылку на категорию с названием на языке lang
+func (c Category) Ref(lang string) CategoryRef {
+	return CategoryRef{ID: c.ID, Label: c.Label(lang), Icon: c.Icon}
+}
+
+// Depth — уровень вложенности: 0 у корневых категорий
+func (c Category) Depth() int {
+	return strings.Count(c.ID, ".")
+}
+
+// HasCategory проверяет, относится ли ссылка к категории id или к одной из её подкатегорий
+func (r CategoryRef) HasCategory(id string) bool {
+	return r.ID == id || strings.HasPrefix(r.ID, id+".")
+}
+
+// CategoryRefs строит ссылки на категории по идентификаторам без повторов и
+// выбирает основную — самую конкретную, при равенстве первую.
+// Неизвестные идентификаторы пропускаются; если не осталось ни одного, место попадает в CategoryOther
+func CategoryRefs(ids []string, lang string) ([]CategoryRef, *CategoryRef) {
+	refs := make([]CategoryRef, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	primaryDepth := -1
+	primary := -1
+
+	for _, id := range ids {
+		c, ok := categoriesByID[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if c.Depth() > primaryDepth {
+			primaryDepth, primary = c.Depth(), len(refs)
+		}
+		refs = append(refs, c.Ref(lang))
+	}
+
+	if len(refs) == 0 {
+		refs = append(refs, categoriesByID[CategoryOther].Ref(lang))
+		primary = 0
+	}
+	ref := refs[primary]
+	return refs, &ref
+}
@@ -0,0 +1,28 @@
+package model
+
+import "math"
+
+// metersPerDegree — длина градуса широты, м
+const metersPerDegree = 111320
+
+// Distance — расстояние между точками по поверхности Земли, м
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000
+	rad1, rad2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad1)*math.Cos(rad2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// CircleBounds возвращает прямоугольник, описанный вокруг круга радиусом radius метров
+func CircleBounds(lat, lon, radius float64) BBox {
+	dLat := radius / metersPerDegree
+	dLon := radius / (metersPerDegree * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+	return BBox{
+		West:  math.Max(lon-dLon, -180),
+		South: math.Max(lat-dLat, -90),
+		East:  math.Min(lon+dLon, 180),
+		North: math.Min(lat+dLat, 90),
+	}
+}
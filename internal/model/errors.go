@@ -0,0 +1,18 @@
+package model
+
+import "errors"
+
+var (
+	// ErrNotFound возвращается, когда запрошенная сущность отсутствует
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists возвращается при конфликте имён
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrUnauthorized возвращается при отсутствующем, неизвестном или отключённом ключе API
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrQuotaExceeded возвращается, когда ключ API исчерпал квоту запросов
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrTooManyItems возвращается, когда пакетный запрос превышает допустимый размер
+	ErrTooManyItems = errors.New("too many items")
+	// ErrInvalidArea возвращается для некорректного прямоугольника или полигона поиска
+	ErrInvalidArea = errors.New("invalid area")
+)
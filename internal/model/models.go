@@ -1,11 +1,41 @@
 package model
 
+import "time"
+
 // LocationResult представляет результат поиска локации с погодой и местами
 type LocationResult struct {
 	Location Location `json:"location"`
 	Weather  *Weather `json:"weather"`
 	Places   []Place  `json:"places"`
-	Error    string   `json:"error,omitempty"`
+	// Clusters — группы близких мест при запросе с уровнем масштаба карты.
+	// Места, попавшие в группы, из Places убираются
+	Clusters []PlaceCluster `json:"clusters,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// PlacesCoverage — круг, места в котором были целиком получены от провайдера
+type PlacesCoverage struct {
+	Lat       float64   `json:"lat"`
+	Lon       float64   `json:"lon"`
+	Radius    float64   `json:"radius"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// PlaceCluster — группа мест, которые на карте выбранного масштаба сливаются в один маркер
+type PlaceCluster struct {
+	ID    string  `json:"id"`
+	Lat   float64 `json:"lat"`
+	Lon   float64 `json:"lon"`
+	Count int     `json:"count"`
+	// Xids — места группы, чтобы клиент мог раскрыть её без повторного запроса
+	Xids       []string          `json:"xids"`
+	Categories []ClusterCategory `json:"categories"`
+}
+
+// ClusterCategory — число мест группы в корневой категории таксономии
+type ClusterCategory struct {
+	CategoryRef
+	Count int `json:"count"`
 }
 
 // Location представляет географическую локацию
@@ -17,6 +47,16 @@ type Location struct {
 	State   string  `json:"state,omitempty"`
 }
 
+// Системы единиц измерения:
+//   - metric: температура в °C, скорость ветра в м/с, расстояния в метрах
+//   - imperial: температура в °F, скорость ветра в милях/ч, расстояния в футах
+//   - standard: температура в K, скорость ветра в м/с, расстояния в метрах
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+	UnitsStandard = "standard"
+)
+
 // Weather представляет данные о погоде
 type Weather struct {
 	Temp        float64 `json:"temp"`
@@ -25,6 +65,20 @@ type Weather struct {
 	Humidity    int     `json:"humidity"`
 	WindSpeed   float64 `json:"wind_speed"`
 	Icon        string  `json:"icon"`
+	Units       string  `json:"units"`
+	// Timezone — сдвиг местного времени от UTC в секундах
+	Timezone int `json:"timezone,omitempty"`
+}
+
+// Forecast представляет прогноз погоды с шагом в несколько часов
+type Forecast struct {
+	Items []ForecastItem `json:"items"`
+}
+
+// ForecastItem представляет прогноз погоды на конкретный момент времени
+type ForecastItem struct {
+	Time time.Time `json:"time"`
+	Weather
 }
 
 // Place представляет интересное место
@@ -39,4 +93,104 @@ type Place struct {
 	Image       string  `json:"image,omitempty"`
 	WebSite     string  `json:"website,omitempty"`
 	Wikipedia   string  `json:"wikipedia,omitempty"`
+	// Categories — категории места в таксономии сервиса, PrimaryCategory — основная из них.
+	// Kinds сохраняет исходные категории провайдера
+	Categories      []CategoryRef `json:"categories,omitempty"`
+	PrimaryCategory *CategoryRef  `json:"primary_category,omitempty"`
+	// OpeningHours — часы работы в формате opening_hours OpenStreetMap
+	OpeningHours string `json:"opening_hours,omitempty"`
+	Units        string `json:"units"`
+	// Score — полезность места с учётом расстояния, категории, погоды и полноты данных,
+	// Reasons — коды причин, из которых сложилась оценка
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Summary представляет начало статьи Википедии о месте
+type Summary struct {
+	Title   string `json:"title"`
+	Extract string `json:"extract"`
+	// Image — главная иллюстрация статьи
+	Image string `json:"image,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// LocationEvent представляет частичный результат получения деталей локации:
+// заполнено ровно одно из полей
+type LocationEvent struct {
+	Weather *Weather `json:"weather,omitempty"`
+	Place   *Place   `json:"place,omitempty"`
+}
+
+// FavoriteList представляет именованный список сохранённых локаций и мест пользователя
+type FavoriteList struct {
+	Name      string     `json:"name"`
+	Locations []Location `json:"locations"`
+	Places    []Place    `json:"places"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Виды записей в истории
+const (
+	HistorySearch   = "search"
+	HistoryLocation = "location"
+)
+
+// HistoryEntry представляет запись истории: поисковый запрос или выбранную локацию
+type HistoryEntry struct {
+	Kind        string    `json:"kind"`
+	UserID      string    `json:"user_id,omitempty"`
+	Query       string    `json:"query,omitempty"`
+	Location    *Location `json:"location,omitempty"`
+	ResultCount int       `json:"result_count"`
+	Time        time.Time `json:"time"`
+}
+
+// PopularStats представляет популярные запросы и локации за период
+type PopularStats struct {
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	Queries        []PopularQuery    `json:"queries"`
+	ZeroHitQueries []PopularQuery    `json:"zero_hit_queries"`
+	Locations      []PopularLocation `json:"locations"`
+}
+
+// PopularQuery представляет поисковый запрос и число его повторений
+type PopularQuery struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// PopularLocation представляет выбранную локацию и число её выборов
+type PopularLocation struct {
+	Location Location `json:"location"`
+	Count    int      `json:"count"`
+}
+
+// APIKey представляет ключ доступа к нашему API для внешнего потребителя.
+// Сам ключ не хранится, только его хеш
+type APIKey struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	DailyQuota int       `json:"daily_quota"`
+	Disabled   bool      `json:"disabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// KeyUsage представляет использование ключа API за один день (UTC)
+type KeyUsage struct {
+	Day           string         `json:"day"`
+	Requests      int            `json:"requests"`
+	ProviderCalls map[string]int `json:"provider_calls"`
+}
+
+// KeyUsageReport представляет суммарное использование ключа за период
+// и его долю в общем числе обращений к провайдерам
+type KeyUsageReport struct {
+	Key           APIKey         `json:"key"`
+	Requests      int            `json:"requests"`
+	ProviderCalls map[string]int `json:"provider_calls"`
+	ProviderShare float64        `json:"provider_share"`
+	Days          []KeyUsage     `json:"days"`
 }
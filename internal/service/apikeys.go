@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"places/internal/model"
+	"sync"
+	"time"
+)
+
+const (
+	// dayLayout — формат ключа дня для счётчиков использования
+	dayLayout = "2006-01-02"
+	// providerCallsFlushInterval — как часто накопленные обращения к провайдерам пишутся в хранилище
+	providerCallsFlushInterval = 10 * time.Second
+)
+
+// providerCallsKey — ключ API и день, за который накоплены обращения к провайдерам
+type providerCallsKey struct {
+	keyID, day string
+}
+
+type apiKeyService struct {
+	store APIKeyStore
+
+	// Обращения к провайдерам копятся в памяти и пишутся не чаще flushInterval:
+	// запись на каждый вызов провайдера стоила бы транзакции с fsync
+	mu            sync.Mutex
+	pending       map[providerCallsKey]map[string]int
+	lastFlush     time.Time
+	flushInterval time.Duration
+}
+
+// NewAPIKeyService создает сервис ключей API
+func NewAPIKeyService(store APIKeyStore) APIKeyService {
+	return &apiKeyService{
+		store:         store,
+		pending:       make(map[providerCallsKey]map[string]int),
+		lastFlush:     time.Now(),
+		flushInterval: providerCallsFlushInterval,
+	}
+}
+
+func (s *apiKeyService) CreateKey(ctx context.Context, name string, dailyQuota int) (*model.APIKey, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return nil, "", err
+	}
+	token := "pk_" + secret
+
+	key := model.APIKey{
+		ID:         id,
+		Name:       name,
+		Prefix:     token[:7],
+		DailyQuota: dailyQuota,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.store.CreateKey(ctx, key, hashToken(token)); err != nil {
+		return nil, "", err
+	}
+
+	return &key, token, nil
+}
+
+func (s *apiKeyService) Keys(ctx context.Context) ([]model.APIKey, error) {
+	return s.store.Keys(ctx)
+}
+
+func (s *apiKeyService) UpdateKey(ctx context.Context, id string, dailyQuota *int, disabled *bool) (*model.APIKey, error) {
+	return s.store.UpdateKey(ctx, id, func(key *model.APIKey) error {
+		if dailyQuota != nil {
+			key.DailyQuota = *dailyQuota
+		}
+		if disabled != nil {
+			key.Disabled = *disabled
+		}
+		return nil
+	})
+}
+
+func (s *apiKeyService) DeleteKey(ctx context.Context, id string) error {
+	return s.store.DeleteKey(ctx, id)
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, token string) (*model.APIKey, error) {
+	if token == "" {
+		return nil, model.ErrUnauthorized
+	}
+
+	key, err := s.store.KeyByHash(ctx, hashToken(token))
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, model.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("api key: %w", err)
+	}
+	if key.Disabled {
+		return nil, model.ErrUnauthorized
+	}
+
+	if _, err := s.store.ConsumeRequest(ctx, key.ID, today(), key.DailyQuota); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *apiKeyService) Usage(ctx context.Context, from, to time.Time) ([]model.KeyUsageReport, error) {
+	// Отчёт учитывает и ещё не записанные обращения
+	s.flush(ctx, s.takePending())
+
+	keys, err := s.store.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]model.KeyUsageReport, 0, len(keys))
+	total := 0
+	for _, key := range keys {
+		days, err := s.store.Usage(ctx, key.ID, from.UTC().Format(dayLayout), to.UTC().Format(dayLayout))
+		if err != nil {
+			return nil, err
+		}
+
+		report := model.KeyUsageReport{
+			Key:           key,
+			ProviderCalls: make(map[string]int),
+			Days:          days,
+		}
+		for _, day := range days {
+			report.Requests += day.Requests
+			for provider, calls := range day.ProviderCalls {
+				report.ProviderCalls[provider] += calls
+				total += calls
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	// Доля каждого ключа в общем числе обращений к провайдерам
+	if total > 0 {
+		for i := range reports {
+			calls := 0
+			for _, c := range reports[i].ProviderCalls {
+				calls += c
+			}
+			reports[i].ProviderShare = float64(calls) / float64(total)
+		}
+	}
+
+	return reports, nil
+}
+
+// RecordProviderCall не должен ломать запрос пользователя: ошибки только логируются.
+// Накопленное пишет тот вызов, который застал истёкший интервал
+func (s *apiKeyService) RecordProviderCall(ctx context.Context, provider string) {
+	keyID := APIKeyIDFromContext(ctx)
+	if keyID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	k := providerCallsKey{keyID: keyID, day: today()}
+	if s.pending[k] == nil {
+		s.pending[k] = make(map[string]int)
+	}
+	s.pending[k][provider]++
+	due := time.Since(s.lastFlush) >= s.flushInterval
+	s.mu.Unlock()
+
+	if due {
+		s.flush(context.WithoutCancel(ctx), s.takePending())
+	}
+}
+
+// takePending забирает накопленные обращения
+func (s *apiKeyService) takePending() map[providerCallsKey]map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = make(map[providerCallsKey]map[string]int)
+	s.lastFlush = time.Now()
+	return pending
+}
+
+func (s *apiKeyService) flush(ctx context.Context, pending map[providerCallsKey]map[string]int) {
+	for k, calls := range pending {
+		if err := s.store.AddProviderCalls(ctx, k.keyID, k.day, calls); err != nil {
+			log.Printf("apikeys: failed to record provider calls %v for key %s: %v", calls, k.keyID, err)
+		}
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func today() string {
+	return time.Now().UTC().Format(dayLayout)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// memKeyStore — APIKeyStore в памяти с той же семантикой квоты, что у boltstore
+type memKeyStore struct {
+	keys   map[string]model.APIKey
+	hashes map[string]string
+	usage  map[string]map[string]*model.KeyUsage
+	// writes — число записей счётчиков обращений к провайдерам
+	writes int
+}
+
+func newMemKeyStore() *memKeyStore {
+	return &memKeyStore{
+		keys:   make(map[string]model.APIKey),
+		hashes: make(map[string]string),
+		usage:  make(map[string]map[string]*model.KeyUsage),
+	}
+}
+
+func (s *memKeyStore) CreateKey(_ context.Context, key model.APIKey, hash string) error {
+	s.keys[key.ID] = key
+	s.hashes[hash] = key.ID
+	return nil
+}
+
+func (s *memKeyStore) KeyByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	key, ok := s.keys[s.hashes[hash]]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &key, nil
+}
+
+func (s *memKeyStore) Keys(context.Context) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *memKeyStore) UpdateKey(_ context.Context, id string, fn func(key *model.APIKey) error) (*model.APIKey, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	if err := fn(&key); err != nil {
+		return nil, err
+	}
+	s.keys[id] = key
+	return &key, nil
+}
+
+func (s *memKeyStore) DeleteKey(_ context.Context, id string) error {
+	delete(s.keys, id)
+	return nil
+}
+
+func (s *memKeyStore) day(id, day string) *model.KeyUsage {
+	if s.usage[id] == nil {
+		s.usage[id] = make(map[string]*model.KeyUsage)
+	}
+	if s.usage[id][day] == nil {
+		s.usage[id][day] = &model.KeyUsage{Day: day, ProviderCalls: make(map[string]int)}
+	}
+	return s.usage[id][day]
+}
+
+func (s *memKeyStore) ConsumeRequest(_ context.Context, id, day string, limit int) (*model.KeyUsage, error) {
+	usage := s.day(id, day)
+	if limit > 0 && usage.Requests >= limit {
+		return nil, model.ErrQuotaExceeded
+	}
+	usage.Requests++
+	return usage, nil
+}
+
+func (s *memKeyStore) AddProviderCalls(_ context.Context, id, day string, calls map[string]int) error {
+	s.writes++
+	for provider, n := range calls {
+		s.day(id, day).ProviderCalls[provider] += n
+	}
+	return nil
+}
+
+func (s *memKeyStore) Usage(_ context.Context, id, fromDay, toDay string) ([]model.KeyUsage, error) {
+	var days []model.KeyUsage
+	for day, usage := range s.usage[id] {
+		if day >= fromDay && day <= toDay {
+			days = append(days, *usage)
+		}
+	}
+	return days, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	store := newMemKeyStore()
+	keys := NewAPIKeyService(store)
+
+	key, token, err := keys.CreateKey(ctx, "mobile", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, "pk_") || key.Prefix != token[:7] {
+		t.Errorf("token %q, prefix %q", token, key.Prefix)
+	}
+	// Хранится только хеш ключа
+	if _, ok := store.hashes[hashToken(token)]; !ok || len(store.hashes) != 1 {
+		t.Errorf("hashes = %v", store.hashes)
+	}
+
+	for _, bad := range []string{"", "pk_unknown", token + "x"} {
+		if _, err := keys.Authenticate(ctx, bad); !errors.Is(err, model.ErrUnauthorized) {
+			t.Errorf("Authenticate(%q) err = %v, want ErrUnauthorized", bad, err)
+		}
+	}
+
+	// Дневная квота — два запроса; неудачные попытки её не тратят
+	for i := range 2 {
+		got, err := keys.Authenticate(ctx, token)
+		if err != nil || got.ID != key.ID {
+			t.Fatalf("request %d: key %+v, err %v", i+1, got, err)
+		}
+	}
+	if _, err := keys.Authenticate(ctx, token); !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("third request err = %v, want ErrQuotaExceeded", err)
+	}
+
+	// Снятие квоты открывает доступ, отключение ключа закрывает
+	unlimited, disabled := 0, true
+	if _, err := keys.UpdateKey(ctx, key.ID, &unlimited, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(ctx, token); err != nil {
+		t.Errorf("unlimited key: %v", err)
+	}
+	if _, err := keys.UpdateKey(ctx, key.ID, nil, &disabled); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(ctx, token); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("disabled key err = %v, want ErrUnauthorized", err)
+	}
+}
+
+// brokenKeyStore не может прочитать ключ
+type brokenKeyStore struct {
+	*memKeyStore
+}
+
+func (brokenKeyStore) KeyByHash(context.Context, string) (*model.APIKey, error) {
+	return nil, errors.New("decode api key: unexpected end of JSON input")
+}
+
+func TestAuthenticateStoreError(t *testing.T) {
+	keys := NewAPIKeyService(brokenKeyStore{newMemKeyStore()})
+	_, err := keys.Authenticate(context.Background(), "pk_any")
+	if err == nil || errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("err = %v, want store error, not ErrUnauthorized", err)
+	}
+}
+
+func TestRecordProviderCallBatches(t *testing.T) {
+	ctx := WithAPIKeyID(context.Background(), "k1")
+	store := newMemKeyStore()
+	keys := NewAPIKeyService(store).(*apiKeyService)
+
+	// До истечения интервала обращения только копятся
+	for range 5 {
+		keys.RecordProviderCall(ctx, "geoapify")
+	}
+	if store.writes != 0 {
+		t.Fatalf("writes = %d before flush interval", store.writes)
+	}
+
+	// Первый вызов после интервала пишет всё накопленное одной записью
+	keys.lastFlush = time.Now().Add(-keys.flushInterval)
+	keys.RecordProviderCall(ctx, "openweather")
+	if store.writes != 1 {
+		t.Fatalf("writes = %d, want 1", store.writes)
+	}
+	calls := store.day("k1", today()).ProviderCalls
+	if calls["geoapify"] != 5 || calls["openweather"] != 1 {
+		t.Errorf("provider calls = %v", calls)
+	}
+
+	keys.RecordProviderCall(ctx, "geoapify")
+	if store.writes != 1 || len(keys.pending) != 1 {
+		t.Errorf("writes = %d, pending = %v", store.writes, keys.pending)
+	}
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	ctx := context.Background()
+	store := newMemKeyStore()
+	keys := NewAPIKeyService(store)
+
+	first, _, _ := keys.CreateKey(ctx, "first", 0)
+	second, _, _ := keys.CreateKey(ctx, "second", 0)
+	for range 3 {
+		keys.RecordProviderCall(WithAPIKeyID(ctx, first.ID), "geoapify")
+	}
+	keys.RecordProviderCall(WithAPIKeyID(ctx, second.ID), "openweather")
+	// Без ключа обращения не учитываются
+	keys.RecordProviderCall(ctx, "geoapify")
+
+	now := time.Now().UTC()
+	reports, err := keys.Usage(ctx, now.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := make(map[string]float64)
+	for _, report := range reports {
+		shares[report.Key.ID] = report.ProviderShare
+	}
+	if math.Abs(shares[first.ID]-0.75) > 1e-9 || math.Abs(shares[second.ID]-0.25) > 1e-9 {
+		t.Errorf("shares = %v", shares)
+	}
+}
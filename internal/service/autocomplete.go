@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"places/internal/model"
+)
+
+/*
+	Подсказки при вводе. Ответы кешируются по нормализованному началу
+	названия: если для "новос" провайдер вернул меньше autocompleteLimit
+	локаций и все они начинаются с "новос", то это все совпадения, и для
+	"новосиб" достаточно отфильтровать их без нового запроса. Если среди
+	ответа есть нечёткие совпадения, провайдер ищет не по началу названия,
+	и о более длинном запросе такой ответ ничего не говорит
+*/
+
+const (
+	// minAutocompleteLength — минимальная длина запроса в символах, короче подсказки не ищутся
+	minAutocompleteLength = 3
+	autocompleteLimit     = 7
+	autocompleteTTL       = time.Hour
+	// autocompleteCacheSize — наибольшее число кешированных запросов
+	autocompleteCacheSize = 5000
+)
+
+func (s *service) Autocomplete(ctx context.Context, query string) ([]model.Location, error) {
+	prefix := normalizeName(query)
+	if utf8.RuneCountInString(prefix) < minAutocompleteLength {
+		return []model.Location{}, nil
+	}
+
+	lang := LanguageFromContext(ctx)
+	if locations, ok := s.autocomplete.lookup(lang, prefix); ok {
+		return locations, nil
+	}
+
+	locations, err := s.geocodingClient.Autocomplete(ctx, query, lang, autocompleteLimit)
+	if err != nil {
+		return nil, err
+	}
+	complete := len(locations) < autocompleteLimit && len(filterByPrefix(locations, prefix)) == len(locations)
+	s.autocomplete.store(lang, prefix, locations, complete)
+	return locations, nil
+}
+
+type prefixEntry struct {
+	locations []model.Location
+	// complete — провайдер вернул все совпадения по началу названия, а не первые autocompleteLimit
+	complete bool
+	expires  time.Time
+}
+
+// prefixCache — кеш подсказок по языку и нормализованному началу названия
+type prefixCache struct {
+	mu      sync.Mutex
+	entries map[string]prefixEntry
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+}
+
+func newPrefixCache(ttl time.Duration, size int) *prefixCache {
+	return &prefixCache{
+		entries: make(map[string]prefixEntry),
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+	}
+}
+
+// lookup ищет ответ для prefix или полный ответ для более короткого начала
+func (c *prefixCache) lookup(lang, prefix string) ([]model.Location, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	runes := []rune(prefix)
+	for n := len(runes); n >= minAutocompleteLength; n-- {
+		entry, ok := c.entries[prefixKey(lang, string(runes[:n]))]
+		if !ok || now.After(entry.expires) {
+			continue
+		}
+		if n == len(runes) {
+			return entry.locations, true
+		}
+		if entry.complete {
+			return filterByPrefix(entry.locations, prefix), true
+		}
+		// Неполный ответ для короткого начала ничего не говорит о более длинном
+		return nil, false
+	}
+	return nil, false
+}
+
+func (c *prefixCache) store(lang, prefix string, locations []model.Location, complete bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	c.entries[prefixKey(lang, prefix)] = prefixEntry{
+		locations: locations,
+		complete:  complete,
+		expires:   now.Add(c.ttl),
+	}
+}
+
+// evict удаляет устаревшие записи, а если их нет — запись, которая устареет первой
+func (c *prefixCache) evict(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.size {
+		delete(c.entries, oldestKey)
+	}
+}
+
+func prefixKey(lang, prefix string) string {
+	return lang + "\x00" + prefix
+}
+
+// filterByPrefix оставляет локации, у которых каждое слово запроса начинает
+// какое-нибудь слово названия, региона или страны
+func filterByPrefix(locations []model.Location, prefix string) []model.Location {
+	words := strings.Fields(prefix)
+	filtered := make([]model.Location, 0, len(locations))
+	for _, location := range locations {
+		fields := strings.Fields(normalizeName(location.Name + " " + location.State + " " + location.Country))
+		if matchesWords(fields, words) {
+			filtered = append(filtered, location)
+		}
+	}
+	return filtered
+}
+
+func matchesWords(fields, words []string) bool {
+	for _, word := range words {
+		found := false
+		for _, field := range fields {
+			if strings.HasPrefix(field, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// autocompleteFunc отвечает локациями из списка, у которых название начинается с запроса
+type autocompleteFunc struct {
+	GeocodingClient
+	locations []model.Location
+	queries   []string
+}
+
+func (a *autocompleteFunc) Autocomplete(_ context.Context, query, _ string, limit int) ([]model.Location, error) {
+	a.queries = append(a.queries, query)
+	var found []model.Location
+	for _, location := range a.locations {
+		if strings.HasPrefix(strings.ToLower(location.Name), strings.ToLower(query)) && len(found) < limit {
+			found = append(found, location)
+		}
+	}
+	return found, nil
+}
+
+func TestAutocomplete(t *testing.T) {
+	geocoder := &autocompleteFunc{locations: []model.Location{
+		{Name: "Новосибирск", State: "Новосибирская область"},
+		{Name: "Новосёлово"},
+		{Name: "Новороссийск"},
+	}}
+	srv := NewService(geocoder, nil, nil, nil).(*service)
+	ctx := context.Background()
+
+	autocomplete := func(query string) []string {
+		t.Helper()
+		locations, err := srv.Autocomplete(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, len(locations))
+		for i, location := range locations {
+			names[i] = location.Name
+		}
+		return names
+	}
+
+	if names := autocomplete("Но"); len(names) != 0 || len(geocoder.queries) != 0 {
+		t.Fatalf("short query: names = %v, queries = %v", names, geocoder.queries)
+	}
+
+	if names := autocomplete("Нов"); len(names) != 3 {
+		t.Fatalf("names = %v", names)
+	}
+	// Полный ответ для "нов" покрывает более длинные начала, в том числе «ё» как «е»
+	if names := autocomplete("новосе"); len(names) != 1 || names[0] != "Новосёлово" {
+		t.Errorf("names = %v", names)
+	}
+	if names := autocomplete("Новос"); len(names) != 2 {
+		t.Errorf("names = %v", names)
+	}
+	if len(geocoder.queries) != 1 {
+		t.Errorf("provider queries = %v, want one", geocoder.queries)
+	}
+
+	// Устаревший кеш не используется
+	srv.autocomplete.now = func() time.Time { return time.Now().Add(2 * autocompleteTTL) }
+	autocomplete("Новос")
+	if len(geocoder.queries) != 2 {
+		t.Errorf("provider queries = %v, want two", geocoder.queries)
+	}
+}
+
+func TestPrefixCacheIncomplete(t *testing.T) {
+	cache := newPrefixCache(time.Hour, 10)
+	cache.store("", "нов", []model.Location{{Name: "Новосибирск"}}, false)
+
+	if _, ok := cache.lookup("", "новос"); ok {
+		t.Error("incomplete entry must not answer a longer prefix")
+	}
+	if _, ok := cache.lookup("en", "нов"); ok {
+		t.Error("entries of another language must not match")
+	}
+	if locations, ok := cache.lookup("", "нов"); !ok || len(locations) != 1 {
+		t.Errorf("exact lookup = %v, %v", locations, ok)
+	}
+}
+
+// fuzzyFunc отвечает одним и тем же списком на любой запрос, как нечёткий поиск
+type fuzzyFunc struct {
+	GeocodingClient
+	locations []model.Location
+	queries   []string
+}
+
+func (f *fuzzyFunc) Autocomplete(_ context.Context, query, _ string, _ int) ([]model.Location, error) {
+	f.queries = append(f.queries, query)
+	return f.locations, nil
+}
+
+func TestAutocompleteFuzzyProvider(t *testing.T) {
+	// «Масква» найдена по опечатке: ответ не полон для более длинного начала
+	geocoder := &fuzzyFunc{locations: []model.Location{{Name: "Москва"}, {Name: "Масква"}}}
+	srv := NewService(geocoder, nil, nil, nil)
+	ctx := context.Background()
+
+	for _, query := range []string{"Моск", "Москв"} {
+		if _, err := srv.Autocomplete(ctx, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(geocoder.queries) != 2 {
+		t.Errorf("provider queries = %v, want both prefixes", geocoder.queries)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"places/internal/model"
+)
+
+type batchService struct {
+	src      Service
+	maxItems int
+	// sem общий для всех пакетных запросов: одновременно обрабатывается
+	// не больше cap(sem) локаций, сколько бы пакетов ни пришло
+	sem chan struct{}
+}
+
+// NewBatchService создает пакетный сервис поверх src.
+// concurrency — общее число одновременно обрабатываемых локаций, maxItems — размер пакета
+func NewBatchService(src Service, concurrency, maxItems int) BatchService {
+	return &batchService{
+		src:      src,
+		maxItems: maxItems,
+		sem:      make(chan struct{}, max(1, concurrency)),
+	}
+}
+
+func (s *batchService) GetLocationDetailsBatch(ctx context.Context, locations []model.Location) ([]model.LocationResult, error) {
+	if len(locations) > s.maxItems {
+		return nil, fmt.Errorf("batch of %d locations exceeds limit of %d: %w", len(locations), s.maxItems, model.ErrTooManyItems)
+	}
+
+	results := make([]model.LocationResult, len(locations))
+
+	var wg sync.WaitGroup
+	for i, location := range locations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.details(ctx, location)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func (s *batchService) details(ctx context.Context, location model.Location) model.LocationResult {
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return model.LocationResult{Location: location, Error: ctx.Err().Error()}
+	}
+
+	result, err := s.src.GetLocationDetails(ctx, location)
+	if err != nil {
+		return model.LocationResult{Location: location, Error: err.Error()}
+	}
+	return *result
+}
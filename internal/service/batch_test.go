@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// detailsFunc подменяет GetLocationDetails, остальные методы Service не используются
+type detailsFunc struct {
+	Service
+	fn func(ctx context.Context, location model.Location) (*model.LocationResult, error)
+}
+
+func (d detailsFunc) GetLocationDetails(ctx context.Context, location model.Location) (*model.LocationResult, error) {
+	return d.fn(ctx, location)
+}
+
+func TestBatchServiceOrderAndErrors(t *testing.T) {
+	src := detailsFunc{fn: func(_ context.Context, location model.Location) (*model.LocationResult, error) {
+		if location.Name == "bad" {
+			return nil, errors.New("provider failed")
+		}
+		// Первые локации отвечают позже, чтобы порядок не совпадал с порядком завершения
+		time.Sleep(time.Duration(10-location.Lat) * time.Millisecond)
+		return &model.LocationResult{Location: location, Weather: &model.Weather{Temp: location.Lat}}, nil
+	}}
+	batch := NewBatchService(src, 3, 10)
+
+	locations := []model.Location{{Name: "a", Lat: 1}, {Name: "bad", Lat: 2}, {Name: "c", Lat: 3}, {Name: "d", Lat: 4}}
+	results, err := batch.GetLocationDetailsBatch(context.Background(), locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(locations) {
+		t.Fatalf("got %d results, want %d", len(results), len(locations))
+	}
+	for i, result := range results {
+		if result.Location != locations[i] {
+			t.Errorf("result %d is for %+v, want %+v", i, result.Location, locations[i])
+		}
+	}
+	if results[1].Error != "provider failed" || results[1].Weather != nil {
+		t.Errorf("failed item = %+v", results[1])
+	}
+	if results[0].Error != "" || results[0].Weather == nil {
+		t.Errorf("successful item = %+v", results[0])
+	}
+}
+
+func TestBatchServiceSharedConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	src := detailsFunc{fn: func(_ context.Context, location model.Location) (*model.LocationResult, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return &model.LocationResult{Location: location}, nil
+	}}
+	batch := NewBatchService(src, 2, 10)
+
+	// Два пакета одновременно делят один лимит
+	done := make(chan struct{})
+	for range 2 {
+		go func() {
+			_, _ = batch.GetLocationDetailsBatch(context.Background(), make([]model.Location, 5))
+			done <- struct{}{}
+		}()
+	}
+	<-done
+	<-done
+
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak.Load())
+	}
+}
+
+func TestBatchServiceTooManyItems(t *testing.T) {
+	batch := NewBatchService(detailsFunc{}, 1, 2)
+
+	_, err := batch.GetLocationDetailsBatch(context.Background(), make([]model.Location, 3))
+	if !errors.Is(err, model.ErrTooManyItems) {
+		t.Fatalf("err = %v, want ErrTooManyItems", err)
+	}
+}
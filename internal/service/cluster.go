@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"places/internal/model"
+)
+
+/*
+	Кластеризация мест для карты в духе supercluster: места переводятся
+	в пиксели веб-меркатора на заданном масштабе, и каждое ещё не занятое
+	место собирает соседей в радиусе clusterRadius пикселей. Места
+	обходятся в порядке ранжирования, поэтому центром группы становится
+	самое интересное место
+*/
+
+const (
+	// MaxZoom — наибольший уровень масштаба карты, для которого строятся группы
+	MaxZoom = 20
+	// clusterRadius — радиус группы в пикселях тайла 256×256
+	clusterRadius = 60
+	tileSize      = 256
+)
+
+// ClusterPlaces группирует места, которые на масштабе zoom ближе clusterRadius пикселей.
+// Возвращает группы из двух и более мест и места, оставшиеся одиночными.
+// Названия категорий в разбивке даются на языке lang
+func ClusterPlaces(places []model.Place, zoom int, lang string) ([]model.PlaceCluster, []model.Place) {
+	zoom = max(0, min(zoom, MaxZoom))
+	scale := tileSize * math.Exp2(float64(zoom))
+
+	type point struct{ x, y float64 }
+	points := make([]point, len(places))
+	for i, place := range places {
+		x, y := mercator(place.Lat, place.Lon)
+		points[i] = point{x * scale, y * scale}
+	}
+
+	var clusters []model.PlaceCluster
+	singles := make([]model.Place, 0, len(places))
+	assigned := make([]bool, len(places))
+
+	for i := range places {
+		if assigned[i] {
+			continue
+		}
+		assigned[i] = true
+
+		members := []int{i}
+		for j := i + 1; j < len(places); j++ {
+			if assigned[j] {
+				continue
+			}
+			if math.Hypot(points[j].x-points[i].x, points[j].y-points[i].y) <= clusterRadius {
+				assigned[j] = true
+				members = append(members, j)
+			}
+		}
+
+		if len(members) == 1 {
+			singles = append(singles, places[i])
+			continue
+		}
+		clusters = append(clusters, newCluster(places, members, zoom, lang))
+	}
+
+	return clusters, singles
+}
+
+func newCluster(places []model.Place, members []int, zoom int, lang string) model.PlaceCluster {
+	cluster := model.PlaceCluster{
+		ID:    fmt.Sprintf("z%d-%s", zoom, places[members[0]].Xid),
+		Count: len(members),
+		Xids:  make([]string, 0, len(members)),
+	}
+
+	counts := make(map[string]int)
+	for _, idx := range members {
+		place := places[idx]
+		cluster.Lat += place.Lat
+		cluster.Lon += place.Lon
+		cluster.Xids = append(cluster.Xids, place.Xid)
+		counts[rootCategory(place)]++
+	}
+	cluster.Lat /= float64(len(members))
+	cluster.Lon /= float64(len(members))
+
+	cluster.Categories = make([]model.ClusterCategory, 0, len(counts))
+	for id, count := range counts {
+		category, _ := model.CategoryByID(id)
+		cluster.Categories = append(cluster.Categories, model.ClusterCategory{
+			CategoryRef: category.Ref(lang),
+			Count:       count,
+		})
+	}
+	// Самые многочисленные категории первыми, при равенстве по идентификатору
+	sort.Slice(cluster.Categories, func(i, j int) bool {
+		a, b := cluster.Categories[i], cluster.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.ID < b.ID
+	})
+
+	return cluster
+}
+
+// rootCategory — корневая категория основной категории места
+func rootCategory(place model.Place) string {
+	if place.PrimaryCategory == nil {
+		return model.CategoryOther
+	}
+	root, _, _ := strings.Cut(place.PrimaryCategory.ID, ".")
+	if _, ok := model.CategoryByID(root); !ok {
+		return model.CategoryOther
+	}
+	return root
+}
+
+// mercator переводит координаты в доли мира в проекции веб-меркатора: x и y от 0 до 1
+func mercator(lat, lon float64) (x, y float64) {
+	// За пределами ±85.05° проекция уходит в бесконечность
+	lat = max(-85.05112878, min(lat, 85.05112878))
+	sin := math.Sin(lat * math.Pi / 180)
+	x = lon/360 + 0.5
+	y = 0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)
+	return x, y
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"places/internal/model"
+)
+
+func TestClusterPlaces(t *testing.T) {
+	museum, cafe := categories("sights.museum"), categories("food.cafe")
+	// На 14-м масштабе 0.001° долготы ≈ 3 пикселя, 0.05° ≈ 146 пикселей
+	places := []model.Place{
+		{Xid: "museum", Lat: 55, Lon: 83, PrimaryCategory: &museum[0]},
+		{Xid: "cafe-1", Lat: 55, Lon: 83.001, PrimaryCategory: &cafe[0]},
+		{Xid: "cafe-2", Lat: 55.001, Lon: 83, PrimaryCategory: &cafe[0]},
+		{Xid: "unknown", Lat: 55.001, Lon: 83.001},
+		{Xid: "far", Lat: 55, Lon: 83.05, PrimaryCategory: &museum[0]},
+	}
+
+	clusters, singles := ClusterPlaces(places, 14, "en")
+	if len(clusters) != 1 || len(singles) != 1 || singles[0].Xid != "far" {
+		t.Fatalf("clusters = %+v, singles = %+v", clusters, singles)
+	}
+
+	cluster := clusters[0]
+	if cluster.ID != "z14-museum" || cluster.Count != 4 ||
+		!slices.Equal(cluster.Xids, []string{"museum", "cafe-1", "cafe-2", "unknown"}) {
+		t.Errorf("cluster = %+v", cluster)
+	}
+	if cluster.Lat != 55.0005 || cluster.Lon != 83.0005 {
+		t.Errorf("centroid = %v,%v", cluster.Lat, cluster.Lon)
+	}
+
+	var breakdown []string
+	for _, category := range cluster.Categories {
+		breakdown = append(breakdown, category.ID+"/"+category.Label)
+		if category.ID == "food" && category.Count != 2 {
+			t.Errorf("food count = %d, want 2", category.Count)
+		}
+	}
+	if !slices.Equal(breakdown, []string{"food/Food", "other/Other", "sights/Sights"}) {
+		t.Errorf("categories = %v", breakdown)
+	}
+
+	// На крупном масштабе те же места не сливаются
+	if clusters, singles := ClusterPlaces(places, MaxZoom, "en"); len(clusters) != 0 || len(singles) != len(places) {
+		t.Errorf("zoom %d: %d clusters, %d singles", MaxZoom, len(clusters), len(singles))
+	}
+}
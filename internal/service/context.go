@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+	"places/internal/model"
+)
+
+type userIDKey struct{}
+
+type apiKeyIDKey struct{}
+
+type languageKey struct{}
+
+type unitsKey struct{}
+
+// WithUserID сохраняет идентификатор пользователя в контексте запроса
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext возвращает идентификатор пользователя или пустую строку для анонимных запросов
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
+
+// KeyUserID привязывает пользователя к ключу API, которым аутентифицирован запрос:
+// у каждого ключа свои пользователи, а без явного пользователя им считается сам ключ.
+// Без ключа пользователь ничем не подтверждён, и запрос считается анонимным
+func KeyUserID(keyID, user string) string {
+	switch {
+	case keyID == "":
+		return ""
+	case user == "":
+		return keyID
+	}
+	return keyID + "/" + user
+}
+
+// WithAPIKeyID сохраняет идентификатор ключа API, которым аутентифицирован запрос
+func WithAPIKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, keyID)
+}
+
+// APIKeyIDFromContext возвращает идентификатор ключа API или пустую строку
+func APIKeyIDFromContext(ctx context.Context) string {
+	keyID, _ := ctx.Value(apiKeyIDKey{}).(string)
+	return keyID
+}
+
+// WithLanguage сохраняет язык ответа (ISO 639-1), выбранный клиентом
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// NormalizeLanguage приводит тег языка клиента к базовому коду ISO 639-1,
+// который понимают провайдеры: "ru-RU" -> "ru", "zh-Hant-TW" -> "zh".
+// Для нераспознанного или неопределённого тега возвращает пустую строку
+func NormalizeLanguage(s string) string {
+	if s == "" {
+		return ""
+	}
+	tag, err := language.Parse(s)
+	if err != nil || tag == language.Und {
+		return ""
+	}
+	base, confidence := tag.Base()
+	if confidence == language.No {
+		return ""
+	}
+	return base.String()
+}
+
+// LanguageFromContext возвращает язык ответа или пустую строку, если клиент его не указал
+func LanguageFromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(languageKey{}).(string)
+	return lang
+}
+
+// WithUnits сохраняет систему единиц, в которой клиент хочет получить ответ
+func WithUnits(ctx context.Context, units string) context.Context {
+	return context.WithValue(ctx, unitsKey{}, units)
+}
+
+// UnitsFromContext возвращает систему единиц ответа, по умолчанию метрическую
+func UnitsFromContext(ctx context.Context) string {
+	if units, ok := ctx.Value(unitsKey{}).(string); ok && units != "" {
+		return units
+	}
+	return model.UnitsMetric
+}
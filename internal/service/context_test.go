@@ -0,0 +1,40 @@
+package service
+
+import "testing"
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ru", "ru"},
+		{"ru-RU", "ru"},
+		{"EN-us", "en"},
+		{"zh-Hant-TW", "zh"},
+		{"zh-CN", "zh"},
+		{"und", ""},
+		{"", ""},
+		{"not a language", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLanguage(tt.in); got != tt.want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKeyUserID(t *testing.T) {
+	tests := []struct {
+		keyID, user, want string
+	}{
+		{"k1", "alice", "k1/alice"},
+		{"k2", "alice", "k2/alice"},
+		{"k1", "", "k1"},
+		{"", "alice", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := KeyUserID(tt.keyID, tt.user); got != tt.want {
+			t.Errorf("KeyUserID(%q, %q) = %q, want %q", tt.keyID, tt.user, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"places/internal/model"
+)
+
+/*
+	Запросы, которые уже являются координатами, не отправляются в геокодер:
+	десятичные градусы ("55.7719, 37.6206"), градусы-минуты-секунды
+	(55°46'19"N 37°37'14"E) и Plus Code ("9G7VQJCC+Q6" или короткий
+	"QJCC+Q6 Москва"). Локация строится сразу, а название подбирается
+	обратным геокодированием, если оно доступно
+*/
+
+// coordinatePart — одна координата: полушарие, знак, градусы, знак градуса,
+// минуты, секунды и полушарие после числа
+const coordinatePart = `(?:([NSEW])\s*)?([+-])?(\d{1,3}(?:\.\d+)?)(?:\s*(°)(?:\s*(\d{1,2}(?:\.\d+)?)\s*'(?:\s*(\d{1,2}(?:\.\d+)?)\s*")?)?)?(?:\s*([NSEW]))?`
+
+var coordinatesPattern = regexp.MustCompile(`^` + coordinatePart + `(\s*[,;]\s*|\s+|)` + coordinatePart + `$`)
+
+// Приведение типографских штрихов и кавычек к ASCII
+var primeReplacer = strings.NewReplacer(
+	"′", "'", "’", "'", "‘", "'",
+	"″", `"`, "”", `"`, "“", `"`, "''", `"`,
+	"º", "°", "˚", "°",
+)
+
+type coordinate struct {
+	value      float64
+	hemisphere string
+	// plain — число без знака градуса и полушария, например "55.7719"
+	plain bool
+}
+
+// parseCoordinates распознаёт пару координат в десятичных градусах или в DMS.
+// Без полушарий первой считается широта, с полушариями порядок любой
+func parseCoordinates(query string) (lat, lon float64, ok bool) {
+	query = strings.ToUpper(primeReplacer.Replace(strings.TrimSpace(query)))
+	m := coordinatesPattern.FindStringSubmatch(query)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	// Без разделителя числа сливаются: допустимо только после полушария или знака градуса
+	if m[8] == "" && m[7] == "" && m[4] == "" {
+		return 0, 0, false
+	}
+	// В "N55.7719 E37.6206" регулярное выражение отдаёт E первой координате
+	if m[1] != "" && m[7] != "" && m[9] == "" {
+		m[7], m[9] = "", m[7]
+	}
+
+	first, ok := parseCoordinate(m[1:8])
+	if !ok {
+		return 0, 0, false
+	}
+	second, ok := parseCoordinate(m[9:16])
+	if !ok {
+		return 0, 0, false
+	}
+
+	// Простые числа должны быть дробными, иначе "1905 2" стало бы точкой
+	if first.plain && second.plain && (!strings.Contains(m[3], ".") || !strings.Contains(m[11], ".")) {
+		return 0, 0, false
+	}
+
+	if isLongitude(first.hemisphere) || isLatitude(second.hemisphere) {
+		first, second = second, first
+	}
+	if isLongitude(first.hemisphere) || isLatitude(second.hemisphere) {
+		return 0, 0, false
+	}
+
+	lat, lon = roundCoordinate(first.value), roundCoordinate(second.value)
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// parseCoordinate разбирает группы одной coordinatePart
+func parseCoordinate(groups []string) (coordinate, bool) {
+	before, sign, deg, degSign, min, sec, after := groups[0], groups[1], groups[2], groups[3], groups[4], groups[5], groups[6]
+	if before != "" && after != "" {
+		return coordinate{}, false
+	}
+	hemisphere := before + after
+	// "-55°S" неоднозначно
+	if sign != "" && hemisphere != "" {
+		return coordinate{}, false
+	}
+
+	value, err := strconv.ParseFloat(deg, 64)
+	if err != nil {
+		return coordinate{}, false
+	}
+	if min != "" {
+		// Дробные градусы с минутами или дробные минуты с секундами противоречивы
+		if strings.Contains(deg, ".") || (sec != "" && strings.Contains(min, ".")) {
+			return coordinate{}, false
+		}
+		minutes, _ := strconv.ParseFloat(min, 64)
+		seconds, _ := strconv.ParseFloat(sec, 64)
+		if minutes >= 60 || seconds >= 60 {
+			return coordinate{}, false
+		}
+		value += minutes/60 + seconds/3600
+	}
+
+	if sign == "-" || hemisphere == "S" || hemisphere == "W" {
+		value = -value
+	}
+	return coordinate{value: value, hemisphere: hemisphere, plain: hemisphere == "" && degSign == ""}, true
+}
+
+func isLatitude(hemisphere string) bool {
+	return hemisphere == "N" || hemisphere == "S"
+}
+
+func isLongitude(hemisphere string) bool {
+	return hemisphere == "E" || hemisphere == "W"
+}
+
+// coordinateLocation строит локацию для точки. Название берётся из обратного
+// геокодирования, а если оно не удалось — сами координаты
+func (s *service) coordinateLocation(ctx context.Context, lat, lon float64) model.Location {
+	location := model.Location{
+		Name: fmt.Sprintf("%.5f, %.5f", lat, lon),
+		Lat:  lat,
+		Lon:  lon,
+	}
+
+	if place, err := s.geocodingClient.ReverseGeocode(ctx, lat, lon, LanguageFromContext(ctx)); err == nil && place.Name != "" {
+		location.Name = place.Name
+		location.Country = place.Country
+		location.State = place.State
+	}
+	return location
+}
+
+// roundCoordinate отбрасывает шум float после разбора: 7 знаков — это сантиметры
+func roundCoordinate(value float64) float64 {
+	return math.Round(value*1e7) / 1e7
+}
+
+// plusCodeCoordinates распознаёт полный Plus Code или короткий код с названием
+// места, по которому он восстанавливается. ok=false значит, что запрос не Plus Code
+func (s *service) plusCodeCoordinates(ctx context.Context, query string) (lat, lon float64, ok bool, err error) {
+	code, locality, _ := strings.Cut(strings.TrimSpace(query), " ")
+	code = strings.ToUpper(strings.TrimSuffix(code, ","))
+	if !plusCodeValid(code) {
+		return 0, 0, false, nil
+	}
+
+	if plusCodeFull(code) {
+		lat, lon, ok = decodePlusCode(code)
+		return lat, lon, ok, nil
+	}
+
+	locality = strings.TrimSpace(locality)
+	if locality == "" {
+		// Короткий код без опорного места не определяет точку
+		return 0, 0, false, nil
+	}
+	references, err := s.geocodingClient.GetLocations(ctx, locality, LanguageFromContext(ctx))
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if len(references) == 0 {
+		return 0, 0, false, fmt.Errorf("plus code locality %q: %w", locality, model.ErrNotFound)
+	}
+	lat, lon, ok = recoverPlusCode(code, references[0].Lat, references[0].Lon)
+	return lat, lon, ok, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"places/internal/model"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		query    string
+		lat, lon float64
+		ok       bool
+	}{
+		{"55.7719, 37.6206", 55.7719, 37.6206, true},
+		{"55.7719 37.6206", 55.7719, 37.6206, true},
+		{"-33.8688;151.2093", -33.8688, 151.2093, true},
+		{`55°46'19"N 37°37'14"E`, 55.7719444, 37.6205556, true},
+		{`55° 46′ 19″ N, 37° 37′ 14″ E`, 55.7719444, 37.6205556, true},
+		{`37°37'14"E 55°46'19"N`, 55.7719444, 37.6205556, true},
+		{"N55.7719 E37.6206", 55.7719, 37.6206, true},
+		{"55.7719S 37.6206W", -55.7719, -37.6206, true},
+		{"55°N37°E", 55, 37, true},
+		{"1905 2", 0, 0, false},
+		{"91.5, 37.1", 0, 0, false},
+		{"55.5, 181.5", 0, 0, false},
+		{"55.5N 37.5N", 0, 0, false},
+		{"-55.5S 37.5E", 0, 0, false},
+		{`55°61'N 37°E`, 0, 0, false},
+		{"Москва", 0, 0, false},
+		{"Новосибирск 2", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			lat, lon, ok := parseCoordinates(tt.query)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (math.Abs(lat-tt.lat) > 1e-6 || math.Abs(lon-tt.lon) > 1e-6) {
+				t.Errorf("got %v, %v, want %v, %v", lat, lon, tt.lat, tt.lon)
+			}
+		})
+	}
+}
+
+func TestPlusCode(t *testing.T) {
+	lat, lon, ok := decodePlusCode("9G7VQJCC+Q6")
+	if !ok || math.Abs(lat-55.7719) > 1e-3 || math.Abs(lon-37.6206) > 1e-3 {
+		t.Fatalf("decode = %v, %v, %v", lat, lon, ok)
+	}
+
+	// Без '+', '+' на нечётной позиции, короткий код, один символ после '+', чужая буква
+	for _, code := range []string{"9G7VQJCC", "9G7VQJC+CQJ", "QJCC+Q6", "9G7VQJCC+Q", "9G7VQJCA+QJ"} {
+		if _, _, ok := decodePlusCode(code); ok {
+			t.Errorf("%s: expected invalid code", code)
+		}
+	}
+
+	// Заполнитель «0» с кодом 9G7V0000+ (область около 110 км) допустим только парами перед '+'
+	if _, _, ok := decodePlusCode("9G7V0000+"); !ok {
+		t.Error("9G7V0000+: expected valid padded code")
+	}
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"padding after separator", "9G7VQJCC+Q0"},
+		{"padding only after separator", "9G7VQJCC+00"},
+		{"padding in short code", "QJ00+"},
+		{"padding at start", "00000000+"},
+		{"odd padding", "9G7VQ000+"},
+		{"padding inside code", "9G700JCC+"},
+		{"digits after padding", "9G7V0000+QJ"},
+		{"padding before and after separator", "9G7V0000+0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, ok := decodePlusCode(tt.code); ok {
+				t.Errorf("%s: expected invalid code", tt.code)
+			}
+			if _, _, ok := recoverPlusCode(tt.code, 55.7558, 37.6173); ok {
+				t.Errorf("%s: expected recovery to fail", tt.code)
+			}
+		})
+	}
+
+	// Опорная точка — центр Москвы в нескольких километрах от кода
+	recoveredLat, recoveredLon, ok := recoverPlusCode("QJCC+Q6", 55.7558, 37.6173)
+	if !ok || recoveredLat != lat || recoveredLon != lon {
+		t.Errorf("recover = %v, %v, %v, want %v, %v", recoveredLat, recoveredLon, ok, lat, lon)
+	}
+	if got := encodePlusCode(lat, lon); got != "9G7VQJCC" {
+		t.Errorf("encode = %s", got)
+	}
+}
+
+// reverseFunc отвечает на обратное геокодирование заданным местом, а на прямое — locations
+type reverseFunc struct {
+	GeocodingClient
+	place     *model.Location
+	locations []model.Location
+	queries   []string
+}
+
+func (r *reverseFunc) ReverseGeocode(context.Context, float64, float64, string) (*model.Location, error) {
+	if r.place == nil {
+		return nil, model.ErrNotFound
+	}
+	return r.place, nil
+}
+
+func (r *reverseFunc) GetLocations(_ context.Context, query, _ string) ([]model.Location, error) {
+	r.queries = append(r.queries, query)
+	return r.locations, nil
+}
+
+func TestSearchLocationsCoordinates(t *testing.T) {
+	ctx := context.Background()
+
+	geocoder := &reverseFunc{place: &model.Location{Name: "Красносельский район", Country: "Россия", State: "Москва"}}
+	srv := NewService(geocoder, nil, nil, nil)
+	locations, err := srv.SearchLocations(ctx, "55.7719, 37.6206")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.Location{Name: "Красносельский район", Country: "Россия", State: "Москва", Lat: 55.7719, Lon: 37.6206}
+	if len(locations) != 1 || locations[0] != want {
+		t.Errorf("locations = %+v", locations)
+	}
+	if len(geocoder.queries) != 0 {
+		t.Errorf("coordinates sent to geocoder: %v", geocoder.queries)
+	}
+
+	// Без обратного геокодирования название — сами координаты
+	geocoder.place = nil
+	locations, err = srv.SearchLocations(ctx, "9G7VQJCC+Q6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 1 || locations[0].Name != "55.77194, 37.62056" {
+		t.Errorf("locations = %+v", locations)
+	}
+
+	geocoder.locations = []model.Location{{Name: "Москва", Lat: 55.7558, Lon: 37.6173}}
+	locations, err = srv.SearchLocations(ctx, "QJCC+Q6 Москва")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 1 || math.Abs(locations[0].Lat-55.7719) > 1e-3 || len(geocoder.queries) != 1 || geocoder.queries[0] != "Москва" {
+		t.Errorf("locations = %+v, queries = %v", locations, geocoder.queries)
+	}
+
+	geocoder.locations = nil
+	if _, err := srv.SearchLocations(ctx, "QJCC+Q6 Нигде"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+
+	// Испорченный код не роняет поиск, а уходит в геокодер как обычный запрос
+	geocoder.queries = nil
+	for _, query := range []string{"9G7VQJCC+Q0", "QJCC+0Q Москва", "9G7V0000+0Q"} {
+		if _, err := srv.SearchLocations(ctx, query); err != nil {
+			t.Errorf("%s: %v", query, err)
+		}
+	}
+	if len(geocoder.queries) != 3 {
+		t.Errorf("queries = %v, want malformed codes geocoded as text", geocoder.queries)
+	}
+}
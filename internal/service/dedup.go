@@ -0,0 +1,223 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"places/internal/model"
+)
+
+/*
+	Провайдер нередко отдаёт одно заведение несколько раз: под разными
+	категориями или как точку и контур OSM в нескольких метрах друг от друга.
+	Дубликаты склеиваются до запроса деталей, чтобы не тратить на них
+	обращения к провайдеру и не показывать одно место дважды
+*/
+
+const (
+	// dedupDistance — максимальное расстояние между дубликатами, м
+	dedupDistance = 75
+	// nameSimilarity — минимальная похожесть названий по расстоянию Левенштейна
+	nameSimilarity = 0.85
+)
+
+// dedupPlaces склеивает места с похожими названиями, стоящие ближе dedupDistance.
+// Порядок сохраняется: дубликат вливается в первое из мест
+func dedupPlaces(places []model.Place) []model.Place {
+	if len(places) < 2 {
+		return places
+	}
+
+	names := make([]string, len(places))
+	for i, place := range places {
+		names[i] = normalizeName(place.Name)
+	}
+
+	merged := make([]model.Place, 0, len(places))
+	// mergedNames — нормализованные названия мест из merged
+	mergedNames := make([]string, 0, len(places))
+
+next:
+	for i, place := range places {
+		for j := range merged {
+			if isDuplicate(merged[j], mergedNames[j], place, names[i]) {
+				merged[j] = mergePlaces(merged[j], place)
+				continue next
+			}
+		}
+		merged = append(merged, place)
+		mergedNames = append(mergedNames, names[i])
+	}
+	return merged
+}
+
+func isDuplicate(a model.Place, aName string, b model.Place, bName string) bool {
+	if a.Xid != "" && a.Xid == b.Xid {
+		return true
+	}
+	if model.Distance(a.Lat, a.Lon, b.Lat, b.Lon) > dedupDistance {
+		return false
+	}
+	return similarNames(aName, bName)
+}
+
+// mergePlaces дополняет место данными дубликата. Xid берётся у места
+// с более конкретной категорией: по нему потом запрашиваются детали
+func mergePlaces(place, duplicate model.Place) model.Place {
+	if categoryDepth(duplicate.PrimaryCategory) > categoryDepth(place.PrimaryCategory) {
+		place.Xid = duplicate.Xid
+		place.PrimaryCategory = duplicate.PrimaryCategory
+	}
+
+	for _, category := range duplicate.Categories {
+		if !hasCategoryID(place.Categories, category.ID) {
+			place.Categories = append(place.Categories, category)
+		}
+	}
+	place.Kinds = mergeKinds(place.Kinds, duplicate.Kinds)
+
+	if duplicate.Distance > 0 && (place.Distance == 0 || duplicate.Distance < place.Distance) {
+		place.Distance = duplicate.Distance
+	}
+	if place.OpeningHours == "" {
+		place.OpeningHours = duplicate.OpeningHours
+	}
+	if place.Description == "" {
+		place.Description = duplicate.Description
+	}
+	if place.Image == "" {
+		place.Image = duplicate.Image
+	}
+	if place.WebSite == "" {
+		place.WebSite = duplicate.WebSite
+	}
+	if place.Wikipedia == "" {
+		place.Wikipedia = duplicate.Wikipedia
+	}
+	return place
+}
+
+func categoryDepth(ref *model.CategoryRef) int {
+	if ref == nil || ref.ID == model.CategoryOther {
+		return -1
+	}
+	category, ok := model.CategoryByID(ref.ID)
+	if !ok {
+		return -1
+	}
+	return category.Depth()
+}
+
+func hasCategoryID(categories []model.CategoryRef, id string) bool {
+	return slices.ContainsFunc(categories, func(c model.CategoryRef) bool { return c.ID == id })
+}
+
+// mergeKinds объединяет списки категорий провайдера через запятую без повторов
+func mergeKinds(a, b string) string {
+	if b == "" || a == b {
+		return a
+	}
+	if a == "" {
+		return b
+	}
+
+	kinds := strings.Split(a, ",")
+	for i := range kinds {
+		kinds[i] = strings.TrimSpace(kinds[i])
+	}
+	for _, kind := range strings.Split(b, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// normalizeName приводит название к виду для сравнения: нижний регистр,
+// «ё» как «е», знаки препинания и кавычки заменены пробелами
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == 'ё':
+			r = 'е'
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+			continue
+		}
+		b.WriteRune(r)
+		space = false
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// similarNames считает названия одинаковыми, если слова одного входят в другое
+// («Кофейня на Пирогова» и «Кофейня Пирогова») или они отличаются опечаткой.
+// Названия с разными номерами («Аптека 1», «Аптека 2») всегда разные
+func similarNames(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if !slices.Equal(numbers(a), numbers(b)) {
+		return false
+	}
+	if containsWords(a, b) || containsWords(b, a) {
+		return true
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1-float64(levenshtein(ra, rb))/float64(longest) >= nameSimilarity
+}
+
+// containsWords проверяет, что все слова short есть в long и покрывают
+// хотя бы половину его слов: «Кафе» не совпадает с «Кафе при гостинице Обь»
+func containsWords(long, short string) bool {
+	longWords, shortWords := strings.Fields(long), strings.Fields(short)
+	if len(shortWords)*2 < len(longWords) {
+		return false
+	}
+	for _, word := range shortWords {
+		if !slices.Contains(longWords, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// numbers возвращает числа из названия в порядке следования
+func numbers(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
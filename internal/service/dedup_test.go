@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"places/internal/model"
+)
+
+func TestDedupPlaces(t *testing.T) {
+	cafe := categories("food.cafe")
+	commercial := categories(model.CategoryOther)
+	// 0.0003° широты ≈ 33 м, 0.001° ≈ 111 м
+	places := []model.Place{
+		{Xid: "node", Name: "Кофейня на Пирогова", Kinds: "commercial", Categories: commercial,
+			PrimaryCategory: &commercial[0], Lat: 55, Lon: 83, Distance: 120},
+		{Xid: "way", Name: "кофейня «Пирогова»", Kinds: "catering.cafe", Categories: cafe,
+			PrimaryCategory: &cafe[0], Lat: 55.0003, Lon: 83, Distance: 100, OpeningHours: "24/7"},
+		{Xid: "typo", Name: "Музей истории Сибири", Lat: 55, Lon: 83},
+		{Xid: "typo-2", Name: "Музей истори Сибири", Lat: 55.0001, Lon: 83},
+		{Xid: "far", Name: "Кофейня на Пирогова", Lat: 55.001, Lon: 83},
+		{Xid: "other", Name: "Кафе", Lat: 55, Lon: 83},
+		{Xid: "other-2", Name: "Кафе при гостинице Обь", Lat: 55, Lon: 83},
+	}
+
+	got := dedupPlaces(places)
+	var xids []string
+	for _, place := range got {
+		xids = append(xids, place.Xid)
+	}
+	want := []string{"way", "typo", "far", "other", "other-2"}
+	if len(xids) != len(want) {
+		t.Fatalf("xids = %v, want %v", xids, want)
+	}
+	for i := range want {
+		if xids[i] != want[i] {
+			t.Fatalf("xids = %v, want %v", xids, want)
+		}
+	}
+
+	merged := got[0]
+	if merged.Name != "Кофейня на Пирогова" || merged.PrimaryCategory.ID != "food.cafe" {
+		t.Errorf("merged = %+v", merged)
+	}
+	if merged.Kinds != "commercial, catering.cafe" || len(merged.Categories) != 2 {
+		t.Errorf("kinds = %q, categories = %v", merged.Kinds, merged.Categories)
+	}
+	if merged.Distance != 100 || merged.OpeningHours != "24/7" {
+		t.Errorf("distance = %v, opening hours = %q", merged.Distance, merged.OpeningHours)
+	}
+}
+
+func TestSimilarNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Starbucks", "STARBUCKS", true},
+		{"Кофейня на Пирогова", "Кофейня Пирогова", true},
+		{"Театр «Глобус»", "Театр Глобус", true},
+		{"Ёлка", "Елка", true},
+		{"Музей истории Сибири", "Музей истори Сибири", true},
+		{"Кафе", "Кафе при гостинице Обь", false},
+		{"Пушкин", "Пушкинская", false},
+		{"Аптека 1", "Аптека 2", false},
+	}
+	for _, tt := range tests {
+		if got := similarNames(normalizeName(tt.a), normalizeName(tt.b)); got != tt.want {
+			t.Errorf("similarNames(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"places/internal/model"
+)
+
+type favoritesService struct {
+	store FavoritesStore
+}
+
+// NewFavoritesService создает сервис избранного поверх хранилища
+func NewFavoritesService(store FavoritesStore) FavoritesService {
+	return &favoritesService{
+		store: store,
+	}
+}
+
+func (s *favoritesService) Lists(ctx context.Context, userID string) ([]model.FavoriteList, error) {
+	return s.store.Lists(ctx, userID)
+}
+
+func (s *favoritesService) List(ctx context.Context, userID, name string) (*model.FavoriteList, error) {
+	return s.store.List(ctx, userID, name)
+}
+
+func (s *favoritesService) AddLocation(ctx context.Context, userID, list string, location model.Location) (*model.FavoriteList, error) {
+	return s.store.UpdateList(ctx, userID, list, func(l *model.FavoriteList) error {
+		// Повторное добавление той же точки обновляет запись, а не дублирует её
+		for i, saved := range l.Locations {
+			if sameCoordinates(saved.Lat, saved.Lon, location.Lat, location.Lon) {
+				l.Locations[i] = location
+				return nil
+			}
+		}
+		l.Locations = append(l.Locations, location)
+		return nil
+	})
+}
+
+func (s *favoritesService) RemoveLocation(ctx context.Context, userID, list string, lat, lon float64) (*model.FavoriteList, error) {
+	return s.store.UpdateList(ctx, userID, list, func(l *model.FavoriteList) error {
+		for i, saved := range l.Locations {
+			if sameCoordinates(saved.Lat, saved.Lon, lat, lon) {
+				l.Locations = append(l.Locations[:i], l.Locations[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("location %f,%f: %w", lat, lon, model.ErrNotFound)
+	})
+}
+
+func (s *favoritesService) AddPlace(ctx context.Context, userID, list string, place model.Place) (*model.FavoriteList, error) {
+	return s.store.UpdateList(ctx, userID, list, func(l *model.FavoriteList) error {
+		for i, saved := range l.Places {
+			if saved.Xid == place.Xid {
+				l.Places[i] = place
+				return nil
+			}
+		}
+		l.Places = append(l.Places, place)
+		return nil
+	})
+}
+
+func (s *favoritesService) RemovePlace(ctx context.Context, userID, list, xid string) (*model.FavoriteList, error) {
+	return s.store.UpdateList(ctx, userID, list, func(l *model.FavoriteList) error {
+		for i, saved := range l.Places {
+			if saved.Xid == xid {
+				l.Places = append(l.Places[:i], l.Places[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("place %s: %w", xid, model.ErrNotFound)
+	})
+}
+
+func (s *favoritesService) RenameList(ctx context.Context, userID, oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	return s.store.RenameList(ctx, userID, oldName, newName)
+}
+
+func (s *favoritesService) DeleteList(ctx context.Context, userID, name string) error {
+	return s.store.DeleteList(ctx, userID, name)
+}
+
+// sameCoordinates сравнивает координаты с точностью ~10 см,
+// чтобы одна и та же точка после JSON не считалась разной
+func sameCoordinates(lat1, lon1, lat2, lon2 float64) bool {
+	const eps = 1e-6
+	return math.Abs(lat1-lat2) < eps && math.Abs(lon1-lon2) < eps
+}
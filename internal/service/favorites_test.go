@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"places/internal/model"
+)
+
+// memFavoritesStore — FavoritesStore в памяти: пользователь -> имя списка -> список
+type memFavoritesStore struct {
+	FavoritesStore
+	lists map[string]map[string]model.FavoriteList
+}
+
+func (s *memFavoritesStore) UpdateList(_ context.Context, userID, name string, fn func(list *model.FavoriteList) error) (*model.FavoriteList, error) {
+	if s.lists[userID] == nil {
+		s.lists[userID] = make(map[string]model.FavoriteList)
+	}
+	list, ok := s.lists[userID][name]
+	if !ok {
+		list = model.FavoriteList{Name: name}
+	}
+	// Как и транзакция хранилища, ошибка fn не сохраняет изменений
+	list.Locations = append([]model.Location(nil), list.Locations...)
+	list.Places = append([]model.Place(nil), list.Places...)
+	if err := fn(&list); err != nil {
+		return nil, err
+	}
+	s.lists[userID][name] = list
+	return &list, nil
+}
+
+func TestFavoritesLocations(t *testing.T) {
+	ctx := context.Background()
+	store := &memFavoritesStore{lists: make(map[string]map[string]model.FavoriteList)}
+	favorites := NewFavoritesService(store)
+
+	if _, err := favorites.AddLocation(ctx, "k1/alice", "trip", model.Location{Name: "Москва", Lat: 55.7558, Lon: 37.6173}); err != nil {
+		t.Fatal(err)
+	}
+	// Та же точка после округления JSON обновляет запись, а не дублирует её
+	list, err := favorites.AddLocation(ctx, "k1/alice", "trip", model.Location{Name: "Кремль", Lat: 55.75580000001, Lon: 37.6173})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Locations) != 1 || list.Locations[0].Name != "Кремль" {
+		t.Errorf("locations = %+v", list.Locations)
+	}
+	list, _ = favorites.AddLocation(ctx, "k1/alice", "trip", model.Location{Name: "Тверь", Lat: 56.8587, Lon: 35.9176})
+	if len(list.Locations) != 2 {
+		t.Fatalf("locations = %+v", list.Locations)
+	}
+
+	list, err = favorites.RemoveLocation(ctx, "k1/alice", "trip", 55.7558, 37.6173)
+	if err != nil || len(list.Locations) != 1 || list.Locations[0].Name != "Тверь" {
+		t.Errorf("after remove: %+v, %v", list, err)
+	}
+	if _, err := favorites.RemoveLocation(ctx, "k1/alice", "trip", 1, 1); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("remove missing err = %v, want ErrNotFound", err)
+	}
+	// Списки разных пользователей не пересекаются
+	if _, ok := store.lists["k2/alice"]; ok {
+		t.Error("other user got a list")
+	}
+}
+
+func TestFavoritesPlaces(t *testing.T) {
+	ctx := context.Background()
+	store := &memFavoritesStore{lists: make(map[string]map[string]model.FavoriteList)}
+	favorites := NewFavoritesService(store)
+
+	_, _ = favorites.AddPlace(ctx, "k1", "museums", model.Place{Xid: "a", Name: "Эрмитаж"})
+	_, _ = favorites.AddPlace(ctx, "k1", "museums", model.Place{Xid: "b", Name: "Русский музей"})
+	list, err := favorites.AddPlace(ctx, "k1", "museums", model.Place{Xid: "a", Name: "Государственный Эрмитаж"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Places) != 2 || list.Places[0].Name != "Государственный Эрмитаж" {
+		t.Errorf("places = %+v", list.Places)
+	}
+
+	list, err = favorites.RemovePlace(ctx, "k1", "museums", "a")
+	if err != nil || len(list.Places) != 1 || list.Places[0].Xid != "b" {
+		t.Errorf("after remove: %+v, %v", list, err)
+	}
+	if _, err := favorites.RemovePlace(ctx, "k1", "museums", "a"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("remove missing err = %v, want ErrNotFound", err)
+	}
+	if got := store.lists["k1"]["museums"]; len(got.Places) != 1 {
+		t.Errorf("failed remove changed the list: %+v", got.Places)
+	}
+
+	// Переименование в то же имя ничего не делает и не обращается к хранилищу
+	if err := favorites.RenameList(ctx, "k1", "museums", "museums"); err != nil {
+		t.Errorf("rename to the same name: %v", err)
+	}
+}
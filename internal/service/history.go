@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"places/internal/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyRecorder оборачивает Service и записывает в историю
+// каждый поисковый запрос и каждую выбранную локацию
+type historyRecorder struct {
+	Service
+	store HistoryStore
+}
+
+// WithHistory добавляет к сервису запись истории запросов
+func WithHistory(srv Service, store HistoryStore) Service {
+	return &historyRecorder{
+		Service: srv,
+		store:   store,
+	}
+}
+
+func (h *historyRecorder) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
+	locations, err := h.Service.SearchLocations(ctx, query)
+	if err == nil {
+		h.record(ctx, model.HistoryEntry{
+			Kind:        model.HistorySearch,
+			Query:       query,
+			ResultCount: len(locations),
+		})
+	}
+	return locations, err
+}
+
+func (h *historyRecorder) GetLocationDetails(ctx context.Context, location model.Location) (*model.LocationResult, error) {
+	result, err := h.Service.GetLocationDetails(ctx, location)
+	if err == nil {
+		h.record(ctx, model.HistoryEntry{
+			Kind:        model.HistoryLocation,
+			Location:    &location,
+			ResultCount: len(result.Places),
+		})
+	}
+	return result, err
+}
+
+// record не влияет на ответ пользователю: ошибка записи только логируется
+func (h *historyRecorder) record(ctx context.Context, entry model.HistoryEntry) {
+	entry.UserID = UserIDFromContext(ctx)
+	entry.Time = time.Now().UTC()
+	if err := h.store.Record(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("history: failed to record %s: %v", entry.Kind, err)
+	}
+}
+
+type historyService struct {
+	store HistoryStore
+}
+
+// NewHistoryService создает сервис чтения истории и статистики
+func NewHistoryService(store HistoryStore) HistoryService {
+	return &historyService{
+		store: store,
+	}
+}
+
+func (s *historyService) History(ctx context.Context, userID string, limit int) ([]model.HistoryEntry, error) {
+	return s.store.UserHistory(ctx, userID, limit)
+}
+
+func (s *historyService) Popular(ctx context.Context, from, to time.Time, limit int) (*model.PopularStats, error) {
+	entries, err := s.store.Entries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make(map[string]int)
+	zeroHits := make(map[string]int)
+	locations := make(map[string]*model.PopularLocation)
+
+	for _, e := range entries {
+		switch e.Kind {
+		case model.HistorySearch:
+			// Запросы, отличающиеся регистром и пробелами, считаем одинаковыми
+			q := strings.ToLower(strings.TrimSpace(e.Query))
+			queries[q]++
+			if e.ResultCount == 0 {
+				zeroHits[q]++
+			}
+		case model.HistoryLocation:
+			if e.Location == nil {
+				continue
+			}
+			key := fmt.Sprintf("%.4f,%.4f", e.Location.Lat, e.Location.Lon)
+			if p, ok := locations[key]; ok {
+				p.Count++
+			} else {
+				locations[key] = &model.PopularLocation{Location: *e.Location, Count: 1}
+			}
+		}
+	}
+
+	stats := &model.PopularStats{
+		From:           from,
+		To:             to,
+		Queries:        topQueries(queries, limit),
+		ZeroHitQueries: topQueries(zeroHits, limit),
+		Locations:      make([]model.PopularLocation, 0, len(locations)),
+	}
+	for _, p := range locations {
+		stats.Locations = append(stats.Locations, *p)
+	}
+	sort.Slice(stats.Locations, func(i, j int) bool {
+		if stats.Locations[i].Count != stats.Locations[j].Count {
+			return stats.Locations[i].Count > stats.Locations[j].Count
+		}
+		return stats.Locations[i].Location.Name < stats.Locations[j].Location.Name
+	})
+	if limit > 0 && len(stats.Locations) > limit {
+		stats.Locations = stats.Locations[:limit]
+	}
+
+	return stats, nil
+}
+
+func topQueries(counts map[string]int, limit int) []model.PopularQuery {
+	result := make([]model.PopularQuery, 0, len(counts))
+	for q, c := range counts {
+		result = append(result, model.PopularQuery{Query: q, Count: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Query < result[j].Query
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
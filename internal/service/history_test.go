@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// memHistoryStore — HistoryStore в памяти; записи хранятся в порядке добавления
+type memHistoryStore struct {
+	entries []model.HistoryEntry
+}
+
+func (s *memHistoryStore) Record(_ context.Context, entry model.HistoryEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memHistoryStore) UserHistory(_ context.Context, userID string, limit int) ([]model.HistoryEntry, error) {
+	var entries []model.HistoryEntry
+	for i := len(s.entries) - 1; i >= 0 && (limit <= 0 || len(entries) < limit); i-- {
+		if s.entries[i].UserID == userID {
+			entries = append(entries, s.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+func (s *memHistoryStore) Entries(_ context.Context, from, to time.Time) ([]model.HistoryEntry, error) {
+	var entries []model.HistoryEntry
+	for _, e := range s.entries {
+		if !e.Time.Before(from) && e.Time.Before(to) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// searchFunc находит по запросу заданные локации; запрос "fail" завершается ошибкой
+type searchFunc struct {
+	Service
+	locations []model.Location
+}
+
+func (s searchFunc) SearchLocations(_ context.Context, query string) ([]model.Location, error) {
+	if query == "fail" {
+		return nil, errors.New("provider failed")
+	}
+	return s.locations, nil
+}
+
+func (s searchFunc) GetLocationDetails(_ context.Context, location model.Location) (*model.LocationResult, error) {
+	return &model.LocationResult{Location: location, Places: make([]model.Place, 3)}, nil
+}
+
+func TestWithHistory(t *testing.T) {
+	store := &memHistoryStore{}
+	srv := WithHistory(searchFunc{locations: make([]model.Location, 2)}, store)
+	ctx := WithUserID(context.Background(), "k1/alice")
+
+	if _, err := srv.SearchLocations(ctx, "Москва"); err != nil {
+		t.Fatal(err)
+	}
+	// Неудачный поиск не записывается
+	if _, err := srv.SearchLocations(ctx, "fail"); err == nil {
+		t.Fatal("expected error")
+	}
+	location := model.Location{Name: "Москва", Lat: 55.7558, Lon: 37.6173}
+	if _, err := srv.GetLocationDetails(ctx, location); err != nil {
+		t.Fatal(err)
+	}
+	// Анонимный запрос попадает в общую статистику без пользователя
+	if _, err := srv.SearchLocations(context.Background(), "Тверь"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.entries) != 3 {
+		t.Fatalf("entries = %+v", store.entries)
+	}
+	search, details, anonymous := store.entries[0], store.entries[1], store.entries[2]
+	if search.Kind != model.HistorySearch || search.Query != "Москва" || search.ResultCount != 2 || search.UserID != "k1/alice" || search.Time.IsZero() {
+		t.Errorf("search entry = %+v", search)
+	}
+	if details.Kind != model.HistoryLocation || details.Location == nil || *details.Location != location || details.ResultCount != 3 {
+		t.Errorf("location entry = %+v", details)
+	}
+	if anonymous.UserID != "" {
+		t.Errorf("anonymous entry user = %q", anonymous.UserID)
+	}
+
+	history, err := NewHistoryService(store).History(ctx, "k1/alice", 1)
+	if err != nil || len(history) != 1 || history[0].Kind != model.HistoryLocation {
+		t.Errorf("history = %+v, %v", history, err)
+	}
+}
+
+func TestPopular(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := from.Add(time.Hour)
+	moscow := model.Location{Name: "Москва", Lat: 55.75581, Lon: 37.61731}
+	tver := model.Location{Name: "Тверь", Lat: 56.8587, Lon: 35.9176}
+	store := &memHistoryStore{entries: []model.HistoryEntry{
+		{Kind: model.HistorySearch, Query: "Москва", ResultCount: 5, Time: at},
+		{Kind: model.HistorySearch, Query: " москва ", ResultCount: 5, Time: at},
+		{Kind: model.HistorySearch, Query: "Тверь", ResultCount: 1, Time: at},
+		{Kind: model.HistorySearch, Query: "Ктулху", ResultCount: 0, Time: at},
+		{Kind: model.HistoryLocation, Location: &moscow, Time: at},
+		// Та же точка в пределах округления до 4 знаков
+		{Kind: model.HistoryLocation, Location: &model.Location{Name: "Москва", Lat: 55.75583, Lon: 37.61729}, Time: at},
+		{Kind: model.HistoryLocation, Location: &tver, Time: at},
+		{Kind: model.HistoryLocation, Time: at},
+		// За пределами интервала
+		{Kind: model.HistorySearch, Query: "Тверь", ResultCount: 1, Time: from.AddDate(0, 0, 2)},
+	}}
+
+	stats, err := NewHistoryService(store).Popular(context.Background(), from, from.AddDate(0, 0, 1), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantQueries := []model.PopularQuery{{Query: "москва", Count: 2}, {Query: "ктулху", Count: 1}}
+	if len(stats.Queries) != 2 || stats.Queries[0] != wantQueries[0] || stats.Queries[1] != wantQueries[1] {
+		t.Errorf("queries = %+v, want %+v", stats.Queries, wantQueries)
+	}
+	if len(stats.ZeroHitQueries) != 1 || stats.ZeroHitQueries[0] != (model.PopularQuery{Query: "ктулху", Count: 1}) {
+		t.Errorf("zero hit queries = %+v", stats.ZeroHitQueries)
+	}
+	if len(stats.Locations) != 2 || stats.Locations[0].Count != 2 || stats.Locations[0].Location != moscow || stats.Locations[1].Location != tver {
+		t.Errorf("locations = %+v", stats.Locations)
+	}
+}
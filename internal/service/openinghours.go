@@ -0,0 +1,156 @@
+package service
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+	Упрощённый разбор opening_hours из OpenStreetMap. Поддерживаются
+	"24/7", дни недели с диапазонами ("Mo-Fr", "Sa,Su"), несколько
+	интервалов времени через запятую, интервалы через полночь и "off".
+	Праздники, месяцы и недели не поддерживаются: для таких строк
+	результат считается неизвестным
+*/
+
+var osmWeekdays = map[string]time.Weekday{
+	"Mo": time.Monday,
+	"Tu": time.Tuesday,
+	"We": time.Wednesday,
+	"Th": time.Thursday,
+	"Fr": time.Friday,
+	"Sa": time.Saturday,
+	"Su": time.Sunday,
+}
+
+// openAt сообщает, открыто ли место в момент now (местное время места).
+// known = false, если строку не удалось разобрать
+func openAt(hours string, now time.Time) (open, known bool) {
+	hours = strings.TrimSpace(hours)
+	if hours == "" {
+		return false, false
+	}
+	if hours == "24/7" {
+		return true, true
+	}
+
+	// Более поздние правила перекрывают ранние для тех же дней
+	for _, rule := range strings.Split(hours, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		days, times, hasDays := splitRule(rule)
+		if hasDays {
+			matches, ok := matchDays(days, now.Weekday())
+			if !ok {
+				return false, false
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		ruleOpen, ok := matchTimes(times, now)
+		if !ok {
+			return false, false
+		}
+		open = ruleOpen
+	}
+
+	// Дни, не попавшие ни в одно правило, считаются выходными
+	return open, true
+}
+
+// splitRule отделяет дни недели от интервалов времени: "Mo-Fr 10:00-17:00"
+func splitRule(rule string) (days, times string, hasDays bool) {
+	first, rest, found := strings.Cut(rule, " ")
+	if _, ok := osmWeekdays[first[:min(2, len(first))]]; ok {
+		if !found {
+			// "Mo-Fr" без времени означает весь день
+			return first, "00:00-24:00", true
+		}
+		return first, strings.TrimSpace(rest), true
+	}
+	return "", rule, false
+}
+
+func matchDays(spec string, day time.Weekday) (matches, ok bool) {
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := osmWeekdays[from]
+		if !ok {
+			return false, false
+		}
+		end := start
+		if isRange {
+			if end, ok = osmWeekdays[to]; !ok {
+				return false, false
+			}
+		}
+
+		// Диапазон может переходить через воскресенье: "Fr-Mo"
+		for d := start; ; d = (d + 1) % 7 {
+			if d == day {
+				return true, true
+			}
+			if d == end {
+				break
+			}
+		}
+	}
+	return false, true
+}
+
+func matchTimes(spec string, now time.Time) (open, ok bool) {
+	if spec == "off" || spec == "closed" {
+		return false, true
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	for _, interval := range strings.Split(spec, ",") {
+		from, to, found := strings.Cut(strings.TrimSpace(interval), "-")
+		if !found {
+			return false, false
+		}
+		start, ok := parseClock(from)
+		if !ok {
+			return false, false
+		}
+		end, ok := parseClock(to)
+		if !ok {
+			return false, false
+		}
+
+		if end <= start {
+			// Интервал через полночь: "22:00-02:00"
+			if minute >= start || minute < end {
+				return true, true
+			}
+		} else if minute >= start && minute < end {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// parseClock переводит "HH:MM" в минуты от начала суток; допускается "24:00"
+func parseClock(s string) (int, bool) {
+	hh, mm, found := strings.Cut(s, ":")
+	if !found || len(hh) != 2 || len(mm) != 2 {
+		return 0, false
+	}
+	h, m := atoi2(hh), atoi2(mm)
+	if h < 0 || m < 0 || h > 24 || m > 59 || (h == 24 && m != 0) {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+func atoi2(s string) int {
+	if s[0] < '0' || s[0] > '9' || s[1] < '0' || s[1] > '9' {
+		return -1
+	}
+	return int(s[0]-'0')*10 + int(s[1]-'0')
+}
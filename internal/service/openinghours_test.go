@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenAt(t *testing.T) {
+	// 2026-10-19 — понедельник
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC)
+	}
+	sunday := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		hours     string
+		now       time.Time
+		wantOpen  bool
+		wantKnown bool
+	}{
+		{"24/7", monday(3, 0), true, true},
+		{"Mo-Fr 10:00-17:00", monday(12, 0), true, true},
+		{"Mo-Fr 10:00-17:00", monday(17, 0), false, true},
+		{"Mo-Fr 10:00-17:00", sunday, false, true},
+		{"Mo-Fr 10:00-17:00; Sa,Su 11:00-15:00", sunday, true, true},
+		{"Mo-Su 08:00-22:00; Mo off", monday(12, 0), false, true},
+		{"Fr-Mo 10:00-14:00", monday(11, 0), true, true},
+		{"Mo 09:00-13:00,14:00-18:00", monday(13, 30), false, true},
+		{"Mo 09:00-13:00,14:00-18:00", monday(15, 0), true, true},
+		{"22:00-02:00", monday(1, 0), true, true},
+		{"10:00-24:00", monday(23, 59), true, true},
+		{"", monday(12, 0), false, false},
+		{"PH off", monday(12, 0), false, false},
+		{"Mo-Fr sunrise-sunset", monday(12, 0), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hours, func(t *testing.T) {
+			open, known := openAt(tt.hours, tt.now)
+			if open != tt.wantOpen || known != tt.wantKnown {
+				t.Errorf("openAt(%q, %v) = %v, %v; want %v, %v", tt.hours, tt.now, open, known, tt.wantOpen, tt.wantKnown)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"places/internal/model"
+)
+
+const (
+	// indexedPlacesLimit — сколько ближайших мест отдаётся из индекса, как у запроса к провайдеру
+	indexedPlacesLimit = 50
+	// coverageSlack — доля радиуса, на которую круг запроса может выйти за покрытие:
+	// повторные запросы по тому же центру города отличаются на метры
+	coverageSlack = 0.05
+)
+
+// indexedPlaces оборачивает PlacesClient пространственным индексом: места из
+// свежего покрытия отдаются без обращения к провайдеру, а при его отказе —
+// из любого пересекающегося покрытия, даже устаревшего
+type indexedPlaces struct {
+	PlacesClient
+	index PlacesIndex
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu sync.Mutex
+	// prunedAt — время последней очистки устаревших покрытий
+	prunedAt time.Time
+}
+
+// IndexPlaces сохраняет все полученные места в index и отвечает из него,
+// пока покрытие круга запроса моложе ttl
+func IndexPlaces(client PlacesClient, index PlacesIndex, ttl time.Duration) PlacesClient {
+	return &indexedPlaces{PlacesClient: client, index: index, ttl: ttl, now: time.Now}
+}
+
+func (p *indexedPlaces) GetPlaces(ctx context.Context, lat, lon, radius float64, lang string) ([]model.Place, error) {
+	coverage, contained, overlapped := p.coverage(ctx, lat, lon, radius, lang)
+	// Места, которых не было в ответе для покрытия, закрылись или ушли из выдачи
+	if contained && p.now().Sub(coverage.FetchedAt) < p.ttl {
+		if places, err := p.indexed(ctx, lat, lon, radius, lang, coverage.FetchedAt); err == nil {
+			return places, nil
+		}
+	}
+
+	places, err := p.PlacesClient.GetPlaces(ctx, lat, lon, radius, lang)
+	if err != nil {
+		// Провайдер недоступен: лучше устаревшие или неполные места, чем никаких
+		if ctx.Err() == nil && overlapped {
+			if indexed, indexErr := p.indexed(ctx, lat, lon, radius, lang, time.Time{}); indexErr == nil && len(indexed) > 0 {
+				log.Printf("places index: provider failed, serving %d indexed places: %v", len(indexed), err)
+				return indexed, nil
+			}
+		}
+		return nil, err
+	}
+
+	fetched := model.PlacesCoverage{Lat: lat, Lon: lon, Radius: radius, FetchedAt: p.now().UTC()}
+	// Ответ пользователю не зависит от записи в индекс
+	if err := p.index.SavePlaces(context.WithoutCancel(ctx), &fetched, lang, fetched.FetchedAt, places); err != nil {
+		log.Printf("places index: failed to save %d places: %v", len(places), err)
+	}
+	p.prune(context.WithoutCancel(ctx))
+	return places, nil
+}
+
+// GetPlacesInArea не отвечает из индекса, пока провайдер доступен: покрытия
+// хранятся кругами, и проверить полноту произвольной области по ним нельзя
+func (p *indexedPlaces) GetPlacesInArea(ctx context.Context, area model.Area, lang string) ([]model.Place, error) {
+	places, err := p.PlacesClient.GetPlacesInArea(ctx, area, lang)
+	if err != nil {
+		if ctx.Err() == nil {
+			if indexed, indexErr := p.indexedInArea(ctx, area, lang); indexErr == nil && len(indexed) > 0 {
+				log.Printf("places index: provider failed, serving %d indexed places: %v", len(indexed), err)
+				return indexed, nil
+			}
+		}
+		return nil, err
+	}
+
+	if err := p.index.SavePlaces(context.WithoutCancel(ctx), nil, lang, p.now().UTC(), places); err != nil {
+		log.Printf("places index: failed to save %d places: %v", len(places), err)
+	}
+	return places, nil
+}
+
+// prune удаляет места и покрытия старше ttl не чаще раза в ttl. Очистка идёт только после
+// успешного ответа провайдера: пока он недоступен, устаревший индекс остаётся запасом
+func (p *indexedPlaces) prune(ctx context.Context) {
+	now := p.now()
+	p.mu.Lock()
+	if now.Sub(p.prunedAt) < p.ttl {
+		p.mu.Unlock()
+		return
+	}
+	p.prunedAt = now
+	p.mu.Unlock()
+
+	if _, err := p.index.Prune(ctx, now.Add(-p.ttl)); err != nil {
+		log.Printf("places index: failed to prune: %v", err)
+	}
+}
+
+// coverage ищет самое свежее покрытие, содержащее круг запроса с точностью до coverageSlack.
+// overlapped сообщает, пересекается ли круг хотя бы с одним покрытием
+func (p *indexedPlaces) coverage(ctx context.Context, lat, lon, radius float64, lang string) (best model.PlacesCoverage, contained, overlapped bool) {
+	coverages, err := p.index.Coverages(ctx, model.CircleBounds(lat, lon, radius), lang)
+	if err != nil {
+		log.Printf("places index: failed to read coverage: %v", err)
+		return best, false, false
+	}
+
+	for _, c := range coverages {
+		distance := model.Distance(lat, lon, c.Lat, c.Lon)
+		if distance < c.Radius+radius {
+			overlapped = true
+		}
+		if distance+radius > c.Radius+radius*coverageSlack {
+			continue
+		}
+		if !contained || c.FetchedAt.After(best.FetchedAt) {
+			best, contained = c, true
+		}
+	}
+	return best, contained, overlapped
+}
+
+// indexed возвращает места из индекса в круге запроса, полученные не раньше since,
+// ближайшие первыми, с расстоянием от центра этого запроса
+func (p *indexedPlaces) indexed(ctx context.Context, lat, lon, radius float64, lang string, since time.Time) ([]model.Place, error) {
+	candidates, err := p.index.Places(ctx, model.CircleBounds(lat, lon, radius), lang, since)
+	if err != nil {
+		return nil, err
+	}
+
+	places := make([]model.Place, 0, len(candidates))
+	for _, place := range candidates {
+		distance := model.Distance(lat, lon, place.Lat, place.Lon)
+		if distance > radius {
+			continue
+		}
+		place.Distance = math.Round(distance)
+		places = append(places, place)
+	}
+
+	sort.SliceStable(places, func(i, j int) bool {
+		return places[i].Distance < places[j].Distance
+	})
+	if len(places) > indexedPlacesLimit {
+		places = places[:indexedPlacesLimit]
+	}
+	return places, nil
+}
+
+// indexedInArea возвращает места из индекса внутри области
+func (p *indexedPlaces) indexedInArea(ctx context.Context, area model.Area, lang string) ([]model.Place, error) {
+	candidates, err := p.index.Places(ctx, area.Bounds(), lang, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	places := make([]model.Place, 0, len(candidates))
+	for _, place := range candidates {
+		if area.Contains(place.Lat, place.Lon) {
+			place.Distance = 0
+			places = append(places, place)
+		}
+		if len(places) == indexedPlacesLimit {
+			break
+		}
+	}
+	return places, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// placesFunc подменяет GetPlaces и считает обращения к провайдеру
+type placesFunc struct {
+	PlacesClient
+	calls int
+	err   error
+}
+
+func (p *placesFunc) GetPlaces(_ context.Context, lat, lon, _ float64, _ string) ([]model.Place, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	// 0.001° широты ≈ 111 м, 0.05° ≈ 5.5 км
+	return []model.Place{
+		{Xid: "near", Name: "Near", Lat: lat + 0.001, Lon: lon, Distance: 111},
+		{Xid: "far", Name: "Far", Lat: lat + 0.05, Lon: lon, Distance: 5560},
+	}, nil
+}
+
+// memPlacesIndex — индекс в памяти: выборка по прямоугольнику без ячеек
+type memPlacesIndex struct {
+	places    map[string]map[string]memPlace
+	coverages map[string][]model.PlacesCoverage
+}
+
+type memPlace struct {
+	place     model.Place
+	indexedAt time.Time
+}
+
+func newMemPlacesIndex() *memPlacesIndex {
+	return &memPlacesIndex{places: make(map[string]map[string]memPlace), coverages: make(map[string][]model.PlacesCoverage)}
+}
+
+func (m *memPlacesIndex) SavePlaces(_ context.Context, coverage *model.PlacesCoverage, lang string, fetchedAt time.Time, places []model.Place) error {
+	if coverage != nil {
+		m.coverages[lang] = append(m.coverages[lang], *coverage)
+	}
+	if m.places[lang] == nil {
+		m.places[lang] = make(map[string]memPlace)
+	}
+	for _, place := range places {
+		// placesFunc отдаёт одни и те же xid в разных точках
+		key := fmt.Sprintf("%s@%f,%f", place.Xid, place.Lat, place.Lon)
+		m.places[lang][key] = memPlace{place: place, indexedAt: fetchedAt}
+	}
+	return nil
+}
+
+func (m *memPlacesIndex) Places(_ context.Context, bbox model.BBox, lang string, since time.Time) ([]model.Place, error) {
+	var places []model.Place
+	for _, p := range m.places[lang] {
+		if bbox.Contains(p.place.Lat, p.place.Lon) && !p.indexedAt.Before(since) {
+			places = append(places, p.place)
+		}
+	}
+	return places, nil
+}
+
+func (m *memPlacesIndex) Coverages(_ context.Context, bbox model.BBox, lang string) ([]model.PlacesCoverage, error) {
+	var coverages []model.PlacesCoverage
+	for _, coverage := range m.coverages[lang] {
+		if bbox.Contains(coverage.Lat, coverage.Lon) {
+			coverages = append(coverages, coverage)
+		}
+	}
+	return coverages, nil
+}
+
+func (m *memPlacesIndex) Prune(_ context.Context, before time.Time) (int, error) {
+	pruned := 0
+	for lang, coverages := range m.coverages {
+		kept := coverages[:0]
+		for _, coverage := range coverages {
+			if coverage.FetchedAt.Before(before) {
+				pruned++
+				continue
+			}
+			kept = append(kept, coverage)
+		}
+		m.coverages[lang] = kept
+	}
+	for _, places := range m.places {
+		for key, p := range places {
+			if p.indexedAt.Before(before) {
+				delete(places, key)
+				pruned++
+			}
+		}
+	}
+	return pruned, nil
+}
+
+func TestIndexPlaces(t *testing.T) {
+	ctx := context.Background()
+	provider := &placesFunc{}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	index := newMemPlacesIndex()
+	client := IndexPlaces(provider, index, time.Hour).(*indexedPlaces)
+	client.now = func() time.Time { return now }
+
+	if _, err := client.GetPlaces(ctx, 55, 83, 10000, "ru"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Круг внутри покрытого отдаётся из индекса с расстоянием от нового центра
+	places, err := client.GetPlaces(ctx, 55.001, 83, 2000, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 1 {
+		t.Fatalf("provider calls = %d, want 1", provider.calls)
+	}
+	if len(places) != 1 || places[0].Xid != "near" || places[0].Distance != 0 {
+		t.Errorf("indexed places = %+v", places)
+	}
+
+	// Другой язык и непокрытая область идут к провайдеру
+	if _, err := client.GetPlaces(ctx, 55, 83, 2000, "en"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetPlaces(ctx, 56, 83, 2000, "ru"); err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 3 {
+		t.Fatalf("provider calls = %d, want 3", provider.calls)
+	}
+
+	// Устаревшее покрытие обновляется, а при отказе провайдера всё равно отдаётся
+	now = now.Add(2 * time.Hour)
+	provider.err = errors.New("provider is down")
+	places, err = client.GetPlaces(ctx, 55, 83, 2000, "ru")
+	if err != nil {
+		t.Fatalf("stale fallback: %v", err)
+	}
+	if provider.calls != 4 || len(places) != 1 || places[0].Distance != 111 {
+		t.Errorf("calls = %d, places = %+v", provider.calls, places)
+	}
+
+	// При отказе хватает и частичного покрытия
+	places, err = client.GetPlaces(ctx, 56.015, 83, 2000, "ru")
+	if err != nil || len(places) != 1 || places[0].Xid != "near" {
+		t.Errorf("partial fallback: places = %+v, err = %v", places, err)
+	}
+
+	// Без покрытия ошибка провайдера возвращается как есть
+	if _, err := client.GetPlaces(ctx, 40, 20, 2000, "ru"); !errors.Is(err, provider.err) {
+		t.Errorf("uncovered error = %v, want %v", err, provider.err)
+	}
+
+	// Первый успешный ответ после ttl удаляет устаревшие покрытия
+	provider.err = nil
+	if _, err := client.GetPlaces(ctx, 57, 83, 2000, "ru"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(index.coverages["ru"]) + len(index.coverages["en"]); got != 1 {
+		t.Errorf("coverages after prune = %d, want only the fresh one", got)
+	}
+	// Места устаревших покрытий удалены вместе с ними
+	if got := len(index.places["ru"]) + len(index.places["en"]); got != 2 {
+		t.Errorf("places after prune = %d, want the two of the fresh answer", got)
+	}
+}
+
+func TestIndexPlacesReplacesStalePlaces(t *testing.T) {
+	ctx := context.Background()
+	index := newMemPlacesIndex()
+	closed := model.Place{Xid: "closed", Name: "Closed", Lat: 55.002, Lon: 83}
+	if err := index.SavePlaces(ctx, nil, "ru", time.Now().Add(-time.Minute), []model.Place{closed}); err != nil {
+		t.Fatal(err)
+	}
+	client := IndexPlaces(&placesFunc{}, index, time.Hour)
+
+	if _, err := client.GetPlaces(ctx, 55, 83, 10000, "ru"); err != nil {
+		t.Fatal(err)
+	}
+	places, err := client.GetPlaces(ctx, 55, 83, 1000, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(places) != 1 || places[0].Xid != "near" {
+		t.Errorf("places = %+v, want the closed place replaced by the fresh answer", places)
+	}
+}
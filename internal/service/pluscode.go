@@ -0,0 +1,162 @@
+package service
+
+import (
+	"math"
+	"strings"
+)
+
+/*
+	Open Location Code (Plus Code). Полный код из 10 символов ("9G7VQJCC+Q6")
+	задаёт ячейку около 14×14 м. Короткий код ("QJCC+Q6") без первых
+	символов восстанавливается по опорной точке рядом, например по городу,
+	указанному после кода
+*/
+
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = '+'
+	plusCodePadding   = '0'
+	// plusCodeSeparatorPosition — позиция '+' в полном коде
+	plusCodeSeparatorPosition = 8
+	// plusCodePairLength — длина части кода, кодирующей пары широта-долгота
+	plusCodePairLength = 10
+	gridRows           = 5
+	gridColumns        = 4
+)
+
+// plusCodeValid проверяет синтаксис полного или короткого кода
+func plusCodeValid(code string) bool {
+	separator := strings.IndexRune(code, plusCodeSeparator)
+	if separator < 0 || separator != strings.LastIndexByte(code, plusCodeSeparator) ||
+		separator > plusCodeSeparatorPosition || separator%2 == 1 {
+		return false
+	}
+
+	padding := strings.IndexRune(code, plusCodePadding)
+	if padding >= 0 {
+		// Заполнитель допустим только в полном коде, парами и сразу перед '+'
+		if separator < plusCodeSeparatorPosition || padding == 0 || padding > separator || padding%2 == 1 {
+			return false
+		}
+		if strings.Trim(code[padding:separator], string(plusCodePadding)) != "" || separator != len(code)-1 {
+			return false
+		}
+	}
+
+	// После '+' либо ничего, либо не меньше двух символов
+	if len(code)-separator-1 == 1 {
+		return false
+	}
+	for _, r := range strings.ReplaceAll(strings.ReplaceAll(code, "+", ""), "0", "") {
+		if !strings.ContainsRune(plusCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// plusCodeFull сообщает, что код полный: первые символы на месте и задают допустимую точку
+func plusCodeFull(code string) bool {
+	if strings.IndexRune(code, plusCodeSeparator) != plusCodeSeparatorPosition {
+		return false
+	}
+	// Первая пара не может выходить за 90° широты и 180° долготы
+	return strings.IndexByte(plusCodeAlphabet, code[0])*20 < 180 &&
+		strings.IndexByte(plusCodeAlphabet, code[1])*20 < 360
+}
+
+// decodePlusCode возвращает центр ячейки полного кода
+func decodePlusCode(code string) (lat, lon float64, ok bool) {
+	code = strings.ToUpper(code)
+	if !plusCodeValid(code) || !plusCodeFull(code) {
+		return 0, 0, false
+	}
+
+	digits := strings.ReplaceAll(strings.ReplaceAll(code, "+", ""), "0", "")
+	latLo, lonLo := -90.0, -180.0
+	latRes, lonRes := 400.0, 400.0
+
+	for i := 0; i < len(digits) && i < plusCodePairLength; i += 2 {
+		latRes /= 20
+		lonRes /= 20
+		latLo += float64(strings.IndexByte(plusCodeAlphabet, digits[i])) * latRes
+		lonLo += float64(strings.IndexByte(plusCodeAlphabet, digits[i+1])) * lonRes
+	}
+	// Символы после десятого уточняют ячейку сеткой 5×4
+	for i := plusCodePairLength; i < len(digits); i++ {
+		latRes /= gridRows
+		lonRes /= gridColumns
+		v := strings.IndexByte(plusCodeAlphabet, digits[i])
+		latLo += float64(v/gridColumns) * latRes
+		lonLo += float64(v%gridColumns) * lonRes
+	}
+
+	lat = math.Min(latLo+latRes/2, 90)
+	lon = lonLo + lonRes/2
+	return roundCoordinate(lat), roundCoordinate(lon), true
+}
+
+// recoverPlusCode восстанавливает короткий код по опорной точке: берётся ближайшая
+// к ней ячейка с такими последними символами
+func recoverPlusCode(short string, refLat, refLon float64) (lat, lon float64, ok bool) {
+	short = strings.ToUpper(short)
+	if !plusCodeValid(short) {
+		return 0, 0, false
+	}
+	if plusCodeFull(short) {
+		return decodePlusCode(short)
+	}
+
+	missing := plusCodeSeparatorPosition - strings.IndexRune(short, plusCodeSeparator)
+	resolution := math.Pow(20, 2-float64(missing)/2)
+	half := resolution / 2
+
+	lat, lon, ok = decodePlusCode(encodePlusCode(refLat, refLon)[:missing] + short)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// Соседняя ячейка может оказаться ближе к опорной точке
+	switch {
+	case refLat+half < lat && lat-resolution >= -90:
+		lat -= resolution
+	case refLat-half > lat && lat+resolution <= 90:
+		lat += resolution
+	}
+	switch {
+	case refLon+half < lon:
+		lon -= resolution
+	case refLon-half > lon:
+		lon += resolution
+	}
+	return roundCoordinate(lat), roundCoordinate(normalizeLon(lon)), true
+}
+
+// encodePlusCode кодирует точку первыми восемью символами полного кода
+func encodePlusCode(lat, lon float64) string {
+	lat = math.Max(-90, math.Min(lat, 90-1e-9)) + 90
+	lon = normalizeLon(lon) + 180
+
+	var b strings.Builder
+	res := 20.0
+	for i := 0; i < plusCodeSeparatorPosition/2; i++ {
+		latDigit := int(lat / res)
+		lonDigit := int(lon / res)
+		lat -= float64(latDigit) * res
+		lon -= float64(lonDigit) * res
+		b.WriteByte(plusCodeAlphabet[latDigit])
+		b.WriteByte(plusCodeAlphabet[lonDigit])
+		res /= 20
+	}
+	return b.String()
+}
+
+func normalizeLon(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon >= 180 {
+		lon -= 360
+	}
+	return lon
+}
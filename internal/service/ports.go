@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"places/internal/model"
+	"time"
 )
 
 /*
@@ -12,21 +13,146 @@ import (
 // Service определяет интерфейс бизнес-логики
 type Service interface {
 	SearchLocations(ctx context.Context, query string) ([]model.Location, error)
+	// Autocomplete подсказывает локации по мере ввода; слишком короткий запрос даёт пустой список
+	Autocomplete(ctx context.Context, query string) ([]model.Location, error)
 	GetLocationDetails(ctx context.Context, location model.Location) (*model.LocationResult, error)
+	// StreamLocationDetails отдаёт погоду и каждое место по мере готовности.
+	// Канал закрывается, когда все запросы завершены или отменён ctx
+	StreamLocationDetails(ctx context.Context, location model.Location) <-chan model.LocationEvent
+
+	// Отдельные шаги для потребителей, которым нужна только часть данных
+	GetWeather(ctx context.Context, location model.Location) (*model.Weather, error)
+	GetForecast(ctx context.Context, location model.Location) (*model.Forecast, error)
+	GetPlaces(ctx context.Context, location model.Location) ([]model.Place, error)
+	// GetPlacesInArea ищет места в видимой части карты или в полигоне, например в границах района
+	GetPlacesInArea(ctx context.Context, area model.Area) ([]model.Place, error)
+	GetPlaceDetails(ctx context.Context, xid string) (*model.Place, error)
+}
+
+// BatchService выполняет GetLocationDetails для нескольких локаций сразу
+type BatchService interface {
+	// GetLocationDetailsBatch возвращает результаты в порядке локаций.
+	// Ошибка отдельной локации записывается в LocationResult.Error и не прерывает остальные
+	GetLocationDetailsBatch(ctx context.Context, locations []model.Location) ([]model.LocationResult, error)
 }
 
+// FavoritesService определяет интерфейс работы с избранным пользователя
+type FavoritesService interface {
+	Lists(ctx context.Context, userID string) ([]model.FavoriteList, error)
+	List(ctx context.Context, userID, name string) (*model.FavoriteList, error)
+	AddLocation(ctx context.Context, userID, list string, location model.Location) (*model.FavoriteList, error)
+	RemoveLocation(ctx context.Context, userID, list string, lat, lon float64) (*model.FavoriteList, error)
+	AddPlace(ctx context.Context, userID, list string, place model.Place) (*model.FavoriteList, error)
+	RemovePlace(ctx context.Context, userID, list, xid string) (*model.FavoriteList, error)
+	RenameList(ctx context.Context, userID, oldName, newName string) error
+	DeleteList(ctx context.Context, userID, name string) error
+}
+
+// HistoryService определяет интерфейс истории поиска и аналитики
+type HistoryService interface {
+	History(ctx context.Context, userID string, limit int) ([]model.HistoryEntry, error)
+	Popular(ctx context.Context, from, to time.Time, limit int) (*model.PopularStats, error)
+}
+
+// APIKeyService определяет интерфейс управления ключами API и их квотами
+type APIKeyService interface {
+	// CreateKey выпускает новый ключ; открытое значение возвращается только здесь
+	CreateKey(ctx context.Context, name string, dailyQuota int) (*model.APIKey, string, error)
+	Keys(ctx context.Context) ([]model.APIKey, error)
+	UpdateKey(ctx context.Context, id string, dailyQuota *int, disabled *bool) (*model.APIKey, error)
+	DeleteKey(ctx context.Context, id string) error
+	// Authenticate проверяет ключ и списывает один запрос из дневной квоты
+	Authenticate(ctx context.Context, token string) (*model.APIKey, error)
+	Usage(ctx context.Context, from, to time.Time) ([]model.KeyUsageReport, error)
+	UsageRecorder
+}
+
+// UsageRecorder учитывает обращения к внешним провайдерам от имени ключа из контекста
+type UsageRecorder interface {
+	RecordProviderCall(ctx context.Context, provider string)
+}
+
+/*
+	Интерфейсы внешних провайдеров. Параметр lang — двухбуквенный код языка
+	ответа (ISO 639-1); пустая строка означает язык провайдера по умолчанию
+*/
+
 // GeocodingClient интерфейс для получения локаций
 type GeocodingClient interface {
-	GetLocations(ctx context.Context, query string) ([]model.Location, error)
+	GetLocations(ctx context.Context, query, lang string) ([]model.Location, error)
+	// Autocomplete ищет локации по началу названия, не больше limit
+	Autocomplete(ctx context.Context, query, lang string, limit int) ([]model.Location, error)
+	// ReverseGeocode находит ближайший к точке объект; model.ErrNotFound, если его нет
+	ReverseGeocode(ctx context.Context, lat, lon float64, lang string) (*model.Location, error)
 }
 
 // WeatherClient интерфейс для получения погоды
 type WeatherClient interface {
-	GetWeather(ctx context.Context, lat, lon float64) (*model.Weather, error)
+	GetWeather(ctx context.Context, lat, lon float64, lang string) (*model.Weather, error)
+	GetForecast(ctx context.Context, lat, lon float64, lang string) (*model.Forecast, error)
 }
 
 // PlacesClient интерфейс для получения мест
 type PlacesClient interface {
-	GetPlaces(ctx context.Context, lat, lon, radius float64) ([]model.Place, error)
-	GetPlaceDetails(ctx context.Context, xid string) (*model.Place, error)
+	GetPlaces(ctx context.Context, lat, lon, radius float64, lang string) ([]model.Place, error)
+	// GetPlacesInArea ищет места внутри прямоугольника или полигона
+	GetPlacesInArea(ctx context.Context, area model.Area, lang string) ([]model.Place, error)
+	GetPlaceDetails(ctx context.Context, xid, lang string) (*model.Place, error)
+}
+
+// SummaryClient интерфейс для получения статей о местах
+type SummaryClient interface {
+	// GetSummary возвращает начало статьи по тегу wikipedia вида "ru:Большой театр"
+	// на языке lang, пустой lang — на языке тега; model.ErrNotFound, если статьи
+	// или её версии на lang нет
+	GetSummary(ctx context.Context, article, lang string) (*model.Summary, error)
+}
+
+// PlacesIndex интерфейс пространственного индекса уже полученных мест.
+// Места и покрытия хранятся отдельно для каждого языка ответа
+type PlacesIndex interface {
+	// SavePlaces сохраняет места, полученные в fetchedAt, и отмечает круг coverage
+	// как покрытый. Без coverage места только пополняют индекс
+	SavePlaces(ctx context.Context, coverage *model.PlacesCoverage, lang string, fetchedAt time.Time, places []model.Place) error
+	// Places возвращает места в прямоугольнике, полученные не раньше since, и, возможно,
+	// места рядом с ним; лишние отсекает вызывающий
+	Places(ctx context.Context, bbox model.BBox, lang string, since time.Time) ([]model.Place, error)
+	// Coverages возвращает покрытия с центрами в прямоугольнике и, возможно, рядом с ним
+	Coverages(ctx context.Context, bbox model.BBox, lang string) ([]model.PlacesCoverage, error)
+	// Prune удаляет места и покрытия, полученные раньше before, и возвращает их число
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// FavoritesStore интерфейс хранилища списков избранного
+type FavoritesStore interface {
+	Lists(ctx context.Context, userID string) ([]model.FavoriteList, error)
+	List(ctx context.Context, userID, name string) (*model.FavoriteList, error)
+	// UpdateList атомарно изменяет список, создавая его при отсутствии
+	UpdateList(ctx context.Context, userID, name string, fn func(list *model.FavoriteList) error) (*model.FavoriteList, error)
+	RenameList(ctx context.Context, userID, oldName, newName string) error
+	DeleteList(ctx context.Context, userID, name string) error
+}
+
+// HistoryStore интерфейс хранилища истории запросов
+type HistoryStore interface {
+	Record(ctx context.Context, entry model.HistoryEntry) error
+	// UserHistory возвращает последние записи пользователя, новые первыми
+	UserHistory(ctx context.Context, userID string, limit int) ([]model.HistoryEntry, error)
+	// Entries возвращает все записи в полуинтервале [from, to)
+	Entries(ctx context.Context, from, to time.Time) ([]model.HistoryEntry, error)
+}
+
+// APIKeyStore интерфейс хранилища ключей API и счётчиков их использования
+type APIKeyStore interface {
+	CreateKey(ctx context.Context, key model.APIKey, hash string) error
+	KeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	Keys(ctx context.Context) ([]model.APIKey, error)
+	UpdateKey(ctx context.Context, id string, fn func(key *model.APIKey) error) (*model.APIKey, error)
+	DeleteKey(ctx context.Context, id string) error
+	// ConsumeRequest увеличивает счётчик запросов за день, если он не превысит limit (0 — без ограничений)
+	ConsumeRequest(ctx context.Context, id, day string, limit int) (*model.KeyUsage, error)
+	// AddProviderCalls прибавляет к счётчикам дня число обращений к каждому провайдеру
+	AddProviderCalls(ctx context.Context, id, day string, calls map[string]int) error
+	// Usage возвращает использование ключа по дням в интервале [fromDay, toDay]
+	Usage(ctx context.Context, id, fromDay, toDay string) ([]model.KeyUsage, error)
 }
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"places/internal/model"
+)
+
+/*
+	Ранжирование мест по полезности. Оценка складывается из расстояния,
+	категории, того, открыто ли место сейчас, полноты данных и погоды:
+	в дождь и мороз выше поднимаются музеи и кафе, в ясную погоду — парки.
+	Коды причин в Reasons стабильны и предназначены для клиентов
+*/
+
+// Коды причин оценки
+const (
+	reasonNearby             = "nearby"
+	reasonSight              = "sight"
+	reasonOpenNow            = "open_now"
+	reasonClosedNow          = "closed_now"
+	reasonHasWikipedia       = "has_wikipedia"
+	reasonHasImage           = "has_image"
+	reasonHasWebsite         = "has_website"
+	reasonHasDescription     = "has_description"
+	reasonIndoorBadWeather   = "indoor_bad_weather"
+	reasonOutdoorBadWeather  = "outdoor_bad_weather"
+	reasonOutdoorGoodWeather = "outdoor_good_weather"
+)
+
+// placesRadius — радиус поиска мест вокруг локации, м
+const placesRadius = 2000
+
+// Веса составляющих оценки
+const (
+	distanceWeight      = 30
+	nearbyDistance      = 500
+	openNowBonus        = 10
+	closedNowPenalty    = 20
+	wikipediaBonus      = 7
+	imageBonus          = 5
+	websiteBonus        = 3
+	descriptionBonus    = 3
+	indoorWeatherBonus  = 15
+	outdoorWeatherBonus = 15
+	outdoorPenalty      = 10
+)
+
+// categoryWeights — базовый интерес к корневым категориям таксономии
+var categoryWeights = map[string]float64{
+	"sights":        12,
+	"culture":       10,
+	"entertainment": 10,
+	"leisure":       8,
+	"nature":        8,
+	"food":          6,
+	"sport":         4,
+	"lodging":       2,
+	"shopping":      2,
+}
+
+// Категории, которые имеют смысл в плохую и хорошую погоду
+var (
+	indoorCategories = []string{
+		"sights.museum", "culture", "entertainment.cinema", "food", "shopping.mall",
+	}
+	outdoorCategories = []string{
+		"leisure.park", "nature", "entertainment.zoo", "entertainment.theme_park", "sights.viewpoint",
+	}
+)
+
+// Пороги погоды в метрической системе
+const (
+	frostCelsius   = -10
+	comfortMinimum = 10
+	comfortMaximum = 28
+)
+
+// rankPlaces оценивает места и возвращает их копию, упорядоченную по убыванию оценки.
+// Ожидает места и погоду в метрической системе, до перевода в единицы клиента
+func rankPlaces(location model.Location, weather *model.Weather, places []model.Place, now time.Time) []model.Place {
+	scorer := newPlaceScorer(location, weather, now)
+	ranked := make([]model.Place, len(places))
+	for i, place := range places {
+		ranked[i] = scorer.score(place)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// placeScorer оценивает места по одному, например в потоке событий, где их нельзя упорядочить
+type placeScorer struct {
+	location   model.Location
+	conditions conditions
+	// now — местное время локации; нулевое, если её часовой пояс неизвестен
+	now time.Time
+}
+
+// newPlaceScorer готовит оценку мест вокруг location. Часовой пояс локации известен
+// только из погоды: без неё часы работы не учитываются, иначе «открыто сейчас»
+// считалось бы по часам сервера
+func newPlaceScorer(location model.Location, weather *model.Weather, now time.Time) placeScorer {
+	scorer := placeScorer{location: location, conditions: weatherConditions(weather)}
+	if weather != nil {
+		scorer.now = now.In(time.FixedZone("", weather.Timezone))
+	}
+	return scorer
+}
+
+func (s placeScorer) score(place model.Place) model.Place {
+	return scorePlace(s.location, place, s.conditions, s.now)
+}
+
+type conditions struct {
+	bad   bool // осадки или сильный мороз
+	sunny bool // ясно и комфортная температура
+}
+
+func weatherConditions(weather *model.Weather) conditions {
+	if weather == nil {
+		return conditions{}
+	}
+
+	temp := toCelsius(weather.Temp, unitsOrMetric(weather.Units))
+	// Код иконки OpenWeather: 01 — ясно, 02 — малооблачно, 09/10 — дождь, 11 — гроза, 13 — снег
+	code := weather.Icon[:min(2, len(weather.Icon))]
+	precipitation := code == "09" || code == "10" || code == "11" || code == "13"
+
+	return conditions{
+		bad:   precipitation || temp < frostCelsius,
+		sunny: (code == "01" || code == "02") && temp >= comfortMinimum && temp <= comfortMaximum,
+	}
+}
+
+func scorePlace(location model.Location, place model.Place, weather conditions, now time.Time) model.Place {
+	var score float64
+	var reasons []string
+
+	distance := place.Distance
+	if distance == 0 {
+		distance = model.Distance(location.Lat, location.Lon, place.Lat, place.Lon)
+	}
+	score += distanceWeight * math.Max(0, 1-distance/placesRadius)
+	if distance < nearbyDistance {
+		reasons = append(reasons, reasonNearby)
+	}
+
+	categoryScore := 0.0
+	for _, category := range place.Categories {
+		root, _, _ := strings.Cut(category.ID, ".")
+		categoryScore = math.Max(categoryScore, categoryWeights[root])
+	}
+	score += categoryScore
+	if hasCategory(place.Categories, "sights") {
+		reasons = append(reasons, reasonSight)
+	}
+
+	if open, known := openNow(place.OpeningHours, now); known {
+		if open {
+			score += openNowBonus
+			reasons = append(reasons, reasonOpenNow)
+		} else {
+			score -= closedNowPenalty
+			reasons = append(reasons, reasonClosedNow)
+		}
+	}
+
+	if place.Wikipedia != "" {
+		score += wikipediaBonus
+		reasons = append(reasons, reasonHasWikipedia)
+	}
+	if place.Image != "" {
+		score += imageBonus
+		reasons = append(reasons, reasonHasImage)
+	}
+	if place.WebSite != "" {
+		score += websiteBonus
+		reasons = append(reasons, reasonHasWebsite)
+	}
+	if place.Description != "" {
+		score += descriptionBonus
+		reasons = append(reasons, reasonHasDescription)
+	}
+
+	indoor := hasCategory(place.Categories, indoorCategories...)
+	outdoor := hasCategory(place.Categories, outdoorCategories...)
+	switch {
+	case weather.bad && indoor:
+		score += indoorWeatherBonus
+		reasons = append(reasons, reasonIndoorBadWeather)
+	case weather.bad && outdoor:
+		score -= outdoorPenalty
+		reasons = append(reasons, reasonOutdoorBadWeather)
+	case weather.sunny && outdoor:
+		score += outdoorWeatherBonus
+		reasons = append(reasons, reasonOutdoorGoodWeather)
+	}
+
+	place.Score = math.Round(score*10) / 10
+	place.Reasons = reasons
+	return place
+}
+
+// openNow — открыто ли место в местное время now; при нулевом now ответа нет
+func openNow(hours string, now time.Time) (open, known bool) {
+	if now.IsZero() {
+		return false, false
+	}
+	return openAt(hours, now)
+}
+
+// hasCategory проверяет, относится ли место к одной из категорий или их подкатегорий
+func hasCategory(categories []model.CategoryRef, wanted ...string) bool {
+	for _, category := range categories {
+		for _, id := range wanted {
+			if category.HasCategory(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+func TestRankPlacesWeather(t *testing.T) {
+	location := model.Location{Lat: 55, Lon: 83}
+	places := []model.Place{
+		{Xid: "park", Categories: categories("leisure.park"), Lat: 55, Lon: 83, Distance: 300},
+		{Xid: "museum", Categories: categories("sights.museum"), Lat: 55, Lon: 83, Distance: 300},
+	}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		weather *model.Weather
+		first   string
+		reason  string
+	}{
+		{"rain boosts indoor", &model.Weather{Temp: 8, Icon: "10d", Units: model.UnitsMetric}, "museum", reasonIndoorBadWeather},
+		{"frost boosts indoor", &model.Weather{Temp: -25, Icon: "01d", Units: model.UnitsMetric}, "museum", reasonIndoorBadWeather},
+		{"sun boosts parks", &model.Weather{Temp: 22, Icon: "01d", Units: model.UnitsMetric}, "park", reasonOutdoorGoodWeather},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankPlaces(location, tt.weather, places, now)
+			if ranked[0].Xid != tt.first {
+				t.Fatalf("first = %s, want %s (%+v)", ranked[0].Xid, tt.first, ranked)
+			}
+			if !slices.Contains(ranked[0].Reasons, tt.reason) {
+				t.Errorf("reasons = %v, want %s", ranked[0].Reasons, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRankPlacesSignals(t *testing.T) {
+	location := model.Location{Lat: 55, Lon: 83}
+	// Понедельник 12:00 по местному времени UTC+7
+	now := time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)
+	weather := &model.Weather{Temp: 5, Icon: "04d", Units: model.UnitsMetric, Timezone: 7 * 3600}
+
+	places := []model.Place{
+		{Xid: "far", Categories: categories("food.cafe"), Lat: 55.017, Lon: 83},
+		{Xid: "closed", Categories: categories("food.cafe"), Distance: 100, OpeningHours: "Mo-Fr 18:00-23:00"},
+		{Xid: "rich", Categories: categories("food.cafe"), Distance: 100, OpeningHours: "Mo-Fr 10:00-14:00",
+			Wikipedia: "ru:Кафе", Image: "https://img", WebSite: "https://site"},
+	}
+
+	ranked := rankPlaces(location, weather, places, now)
+	order := []string{ranked[0].Xid, ranked[1].Xid, ranked[2].Xid}
+	if !slices.Equal(order, []string{"rich", "closed", "far"}) {
+		t.Fatalf("order = %v", order)
+	}
+
+	want := []string{reasonNearby, reasonOpenNow, reasonHasWikipedia, reasonHasImage, reasonHasWebsite}
+	if !slices.Equal(ranked[0].Reasons, want) {
+		t.Errorf("reasons = %v, want %v", ranked[0].Reasons, want)
+	}
+	if !slices.Contains(ranked[1].Reasons, reasonClosedNow) {
+		t.Errorf("closed place reasons = %v", ranked[1].Reasons)
+	}
+	if ranked[2].Score >= ranked[1].Score {
+		t.Errorf("far place (about 1.9 km) scored %v, closed nearby place %v", ranked[2].Score, ranked[1].Score)
+	}
+}
+
+func categories(ids ...string) []model.CategoryRef {
+	refs, _ := model.CategoryRefs(ids, "en")
+	return refs
+}
+
+func TestRankPlacesWithoutTimezone(t *testing.T) {
+	// Без погоды часовой пояс локации неизвестен: часы работы не учитываются
+	places := []model.Place{{Xid: "cafe", Categories: categories("food.cafe"), Distance: 100, OpeningHours: "Mo-Fr 18:00-23:00"}}
+	ranked := rankPlaces(model.Location{Lat: 55, Lon: 83}, nil, places, time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC))
+	if slices.Contains(ranked[0].Reasons, reasonClosedNow) || slices.Contains(ranked[0].Reasons, reasonOpenNow) {
+		t.Errorf("reasons = %v, want no open-now signal", ranked[0].Reasons)
+	}
+}
+
+// weatherFunc отдаёт одну и ту же погоду
+type weatherFunc struct {
+	WeatherClient
+	weather model.Weather
+}
+
+func (w weatherFunc) GetWeather(context.Context, float64, float64, string) (*model.Weather, error) {
+	weather := w.weather
+	return &weather, nil
+}
+
+// listedPlaces отдаёт список мест и детали по xid
+type listedPlaces struct {
+	placeDetailsFunc
+	list []model.Place
+}
+
+func (p *listedPlaces) GetPlaces(context.Context, float64, float64, float64, string) ([]model.Place, error) {
+	return p.list, nil
+}
+
+func rankedTestPlaces() *listedPlaces {
+	park := model.Place{Xid: "park", Name: "Парк", Categories: categories("leisure.park"), Distance: 300,
+		OpeningHours: "Mo-Su 18:00-23:00"}
+	museum := model.Place{Xid: "museum", Name: "Музей", Categories: categories("sights.museum"), Distance: 300}
+	return &listedPlaces{
+		placeDetailsFunc: placeDetailsFunc{details: map[string]model.Place{"park": park, "museum": museum}},
+		list:             []model.Place{park, museum},
+	}
+}
+
+func TestGetPlacesRanked(t *testing.T) {
+	srv := NewService(nil, nil, rankedTestPlaces(), nil)
+
+	places, err := srv.GetPlaces(context.Background(), model.Location{Lat: 55, Lon: 83})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(places) != 2 || places[0].Xid != "museum" || places[0].Score <= places[1].Score {
+		t.Fatalf("places = %+v, want the museum first by category", places)
+	}
+	if slices.Contains(places[1].Reasons, reasonClosedNow) {
+		t.Errorf("reasons = %v, want no open-now signal without weather", places[1].Reasons)
+	}
+}
+
+func TestStreamLocationDetailsScores(t *testing.T) {
+	rain := model.Weather{Temp: 8, Icon: "10d", Units: model.UnitsMetric}
+	srv := NewService(nil, weatherFunc{weather: rain}, rankedTestPlaces(), nil)
+
+	scores := make(map[string]model.Place)
+	for event := range srv.StreamLocationDetails(context.Background(), model.Location{Lat: 55, Lon: 83}) {
+		if event.Place != nil {
+			scores[event.Place.Xid] = *event.Place
+		}
+	}
+	if len(scores) != 2 {
+		t.Fatalf("places = %+v", scores)
+	}
+	// Оценка учитывает погоду, даже если место пришло раньше неё
+	if !slices.Contains(scores["museum"].Reasons, reasonIndoorBadWeather) ||
+		!slices.Contains(scores["park"].Reasons, reasonOutdoorBadWeather) {
+		t.Errorf("reasons: museum %v, park %v", scores["museum"].Reasons, scores["park"].Reasons)
+	}
+	if scores["museum"].Score <= scores["park"].Score {
+		t.Errorf("scores: museum %v, park %v", scores["museum"].Score, scores["park"].Score)
+	}
+}
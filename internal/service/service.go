@@ -4,25 +4,84 @@ import (
 	"context"
 	"places/internal/model"
 	"sync"
+	"time"
 )
 
 type service struct {
 	geocodingClient GeocodingClient
 	weatherClient   WeatherClient
 	placesClient    PlacesClient
+	summaryClient   SummaryClient
+	autocomplete    *prefixCache
 }
 
-// NewService создает новый экземпляр сервиса
-func NewService(geocoding GeocodingClient, weather WeatherClient, places PlacesClient) Service {
+// NewService создает новый экземпляр сервиса; без summaries статьи о местах не запрашиваются
+func NewService(geocoding GeocodingClient, weather WeatherClient, places PlacesClient, summaries SummaryClient) Service {
 	return &service{
 		geocodingClient: geocoding,
 		weatherClient:   weather,
 		placesClient:    places,
+		summaryClient:   summaries,
+		autocomplete:    newPrefixCache(autocompleteTTL, autocompleteCacheSize),
 	}
 }
 
 func (s *service) SearchLocations(ctx context.Context, query string) ([]model.Location, error) {
-	return s.geocodingClient.GetLocations(ctx, query)
+	if lat, lon, ok := parseCoordinates(query); ok {
+		return []model.Location{s.coordinateLocation(ctx, lat, lon)}, nil
+	}
+	if lat, lon, ok, err := s.plusCodeCoordinates(ctx, query); err != nil || ok {
+		if err != nil {
+			return nil, err
+		}
+		return []model.Location{s.coordinateLocation(ctx, lat, lon)}, nil
+	}
+	return s.geocodingClient.GetLocations(ctx, query, LanguageFromContext(ctx))
+}
+
+func (s *service) GetWeather(ctx context.Context, location model.Location) (*model.Weather, error) {
+	w, err := s.weatherClient.GetWeather(ctx, location.Lat, location.Lon, LanguageFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return convertWeather(w, UnitsFromContext(ctx)), nil
+}
+
+func (s *service) GetForecast(ctx context.Context, location model.Location) (*model.Forecast, error) {
+	f, err := s.weatherClient.GetForecast(ctx, location.Lat, location.Lon, LanguageFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return convertForecast(f, UnitsFromContext(ctx)), nil
+}
+
+func (s *service) GetPlaces(ctx context.Context, location model.Location) ([]model.Place, error) {
+	ps, err := s.placesClient.GetPlaces(ctx, location.Lat, location.Lon, placesRadius, LanguageFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	// Без погоды оценка учитывает расстояние, категорию и полноту данных
+	return convertPlaces(rankPlaces(location, nil, dedupPlaces(ps), time.Now()), UnitsFromContext(ctx)), nil
+}
+
+func (s *service) GetPlacesInArea(ctx context.Context, area model.Area) ([]model.Place, error) {
+	if err := area.Validate(); err != nil {
+		return nil, err
+	}
+	ps, err := s.placesClient.GetPlacesInArea(ctx, area, LanguageFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return convertPlaces(dedupPlaces(ps), UnitsFromContext(ctx)), nil
+}
+
+func (s *service) GetPlaceDetails(ctx context.Context, xid string) (*model.Place, error) {
+	p, err := s.placesClient.GetPlaceDetails(ctx, xid, LanguageFromContext(ctx))
+	if err != nil || p == nil {
+		return p, err
+	}
+	converted := convertPlace(withSummary(*p, s.summary(ctx, p.Wikipedia)), UnitsFromContext(ctx))
+	return &converted, nil
 }
 
 func (s *service) GetLocationDetails(ctx context.Context, location model.Location) (*model.LocationResult, error) {
@@ -37,7 +96,7 @@ func (s *service) GetLocationDetails(ctx context.Context, location model.Locatio
 	// Погода
 	go func() {
 		defer wg.Done()
-		if w, err := s.weatherClient.GetWeather(ctx, location.Lat, location.Lon); err == nil {
+		if w, err := s.weatherClient.GetWeather(ctx, location.Lat, location.Lon, LanguageFromContext(ctx)); err == nil {
 			weatherCh <- w
 		}
 	}()
@@ -45,8 +104,8 @@ func (s *service) GetLocationDetails(ctx context.Context, location model.Locatio
 	// Места
 	go func() {
 		defer wg.Done()
-		if ps, err := s.placesClient.GetPlaces(ctx, location.Lat, location.Lon, 2000); err == nil {
-			placesCh <- s.enrichPlacesWithDetails(ctx, ps)
+		if ps, err := s.placesClient.GetPlaces(ctx, location.Lat, location.Lon, placesRadius, LanguageFromContext(ctx)); err == nil {
+			placesCh <- s.enrichPlacesWithDetails(ctx, dedupPlaces(ps))
 		}
 	}()
 
@@ -56,11 +115,14 @@ func (s *service) GetLocationDetails(ctx context.Context, location model.Locatio
 	close(placesCh)
 
 	// Читаем без риска блокировки
-	if w, ok := <-weatherCh; ok && w != nil {
-		result.Weather = w
+	units := UnitsFromContext(ctx)
+	weather := <-weatherCh
+	if weather != nil {
+		result.Weather = convertWeather(weather, units)
 	}
+	// Места ранжируются с учётом погоды до перевода в единицы клиента
 	if ps, ok := <-placesCh; ok && ps != nil {
-		result.Places = ps
+		result.Places = convertPlaces(rankPlaces(location, weather, ps, time.Now()), units)
 	}
 
 	return result, nil
@@ -73,26 +135,85 @@ func (s *service) enrichPlacesWithDetails(ctx context.Context, places []model.Pl
 
 	detailedPlaces := make([]model.Place, len(places))
 	var wg sync.WaitGroup // ждёт завершения всех горутин
-	var mu sync.Mutex     // мьютекс, чтобы безопасно записывать данные в общий массив detailedPlaces
 
 	for i, place := range places {
 		wg.Add(1)
 		go func(idx int, p model.Place) {
 			defer wg.Done()
-
-			details, err := s.placesClient.GetPlaceDetails(ctx, p.Xid)
-			if err == nil && details != nil {
-				mu.Lock()
-				detailedPlaces[idx] = *details
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				detailedPlaces[idx] = p
-				mu.Unlock()
-			}
+			// Каждая горутина пишет только в свой элемент
+			detailedPlaces[idx] = s.placeDetails(ctx, p)
 		}(i, place)
 	}
 
 	wg.Wait()
 	return detailedPlaces
 }
+
+func (s *service) StreamLocationDetails(ctx context.Context, location model.Location) <-chan model.LocationEvent {
+	events := make(chan model.LocationEvent)
+	units := UnitsFromContext(ctx)
+
+	// send не блокируется навсегда, если потребитель ушёл и отменил ctx
+	send := func(e model.LocationEvent) {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// weatherDone закрывается, когда погода получена или запрос к ней не удался:
+	// от неё зависит оценка мест
+	var weather *model.Weather
+	weatherDone := make(chan struct{})
+
+	// Погода
+	go func() {
+		defer wg.Done()
+		w, err := s.weatherClient.GetWeather(ctx, location.Lat, location.Lon, LanguageFromContext(ctx))
+		if err == nil && w != nil {
+			weather = w
+		}
+		close(weatherDone)
+		if weather != nil {
+			send(model.LocationEvent{Weather: convertWeather(weather, units)})
+		}
+	}()
+
+	// Места: каждое отдаём с оценкой сразу после получения деталей, упорядочивает клиент
+	go func() {
+		defer wg.Done()
+		ps, err := s.placesClient.GetPlaces(ctx, location.Lat, location.Lon, placesRadius, LanguageFromContext(ctx))
+		if err != nil {
+			return
+		}
+
+		var scorer placeScorer
+		scorerReady := sync.OnceFunc(func() {
+			<-weatherDone
+			scorer = newPlaceScorer(location, weather, time.Now())
+		})
+
+		var placesWg sync.WaitGroup
+		for _, place := range dedupPlaces(ps) {
+			placesWg.Add(1)
+			go func(p model.Place) {
+				defer placesWg.Done()
+				p = s.placeDetails(ctx, p)
+				scorerReady()
+				p = convertPlace(scorer.score(p), units)
+				send(model.LocationEvent{Place: &p})
+			}(place)
+		}
+		placesWg.Wait()
+	}()
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events
+}
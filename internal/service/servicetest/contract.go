@@ -0,0 +1,297 @@
+// Package servicetest содержит контрактные тесты портов провайдеров.
+// Любая реализация GeocodingClient, WeatherClient, PlacesClient и SummaryClient должна их проходить
+package servicetest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"places/internal/model"
+	"places/internal/service"
+)
+
+// TestGeocodingClient проверяет реализацию GeocodingClient.
+// query должен находить хотя бы одну локацию
+func TestGeocodingClient(t *testing.T, client service.GeocodingClient, query string) {
+	t.Helper()
+
+	t.Run("GetLocations", func(t *testing.T) {
+		locations, err := client.GetLocations(context.Background(), query, "")
+		if err != nil {
+			t.Fatalf("GetLocations(%q): %v", query, err)
+		}
+		if len(locations) == 0 {
+			t.Fatalf("GetLocations(%q) returned no locations", query)
+		}
+		for i, location := range locations {
+			checkCoordinates(t, "location", i, location.Lat, location.Lon)
+		}
+	})
+
+	t.Run("Autocomplete", func(t *testing.T) {
+		prefix := string([]rune(query)[:min(3, len([]rune(query)))])
+		locations, err := client.Autocomplete(context.Background(), prefix, "", 5)
+		if err != nil {
+			t.Fatalf("Autocomplete(%q): %v", prefix, err)
+		}
+		for i, location := range locations {
+			checkCoordinates(t, "location", i, location.Lat, location.Lon)
+		}
+	})
+
+	t.Run("ReverseGeocode", func(t *testing.T) {
+		locations, err := client.GetLocations(context.Background(), query, "")
+		if err != nil || len(locations) == 0 {
+			t.Skipf("GetLocations(%q) found nothing to reverse: %v", query, err)
+		}
+		location, err := client.ReverseGeocode(context.Background(), locations[0].Lat, locations[0].Lon, "")
+		if err != nil {
+			t.Fatalf("ReverseGeocode(%v, %v): %v", locations[0].Lat, locations[0].Lon, err)
+		}
+		if location == nil {
+			t.Fatal("ReverseGeocode returned nil location without error")
+		}
+		checkCoordinates(t, "location", 0, location.Lat, location.Lon)
+	})
+
+	t.Run("GetLocationsWithLanguage", func(t *testing.T) {
+		if _, err := client.GetLocations(context.Background(), query, "en"); err != nil {
+			t.Fatalf("GetLocations(%q, en): %v", query, err)
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		if _, err := client.GetLocations(canceledContext(), query, ""); err == nil {
+			t.Error("GetLocations with canceled context: expected error")
+		}
+		if _, err := client.Autocomplete(canceledContext(), query, "", 5); err == nil {
+			t.Error("Autocomplete with canceled context: expected error")
+		}
+		if _, err := client.ReverseGeocode(canceledContext(), 55, 83, ""); err == nil {
+			t.Error("ReverseGeocode with canceled context: expected error")
+		}
+	})
+}
+
+// TestWeatherClient проверяет реализацию WeatherClient в точке lat, lon
+func TestWeatherClient(t *testing.T, client service.WeatherClient, lat, lon float64) {
+	t.Helper()
+
+	t.Run("GetWeather", func(t *testing.T) {
+		weather, err := client.GetWeather(context.Background(), lat, lon, "")
+		if err != nil {
+			t.Fatalf("GetWeather: %v", err)
+		}
+		if weather == nil {
+			t.Fatal("GetWeather returned nil weather without error")
+		}
+		checkWeather(t, *weather)
+	})
+
+	t.Run("GetForecast", func(t *testing.T) {
+		forecast, err := client.GetForecast(context.Background(), lat, lon, "")
+		if err != nil {
+			t.Fatalf("GetForecast: %v", err)
+		}
+		if forecast == nil {
+			t.Fatal("GetForecast returned nil forecast without error")
+		}
+		for i, item := range forecast.Items {
+			checkWeather(t, item.Weather)
+			if i > 0 && item.Time.Before(forecast.Items[i-1].Time) {
+				t.Errorf("forecast item %d at %v is before previous item", i, item.Time)
+			}
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		if _, err := client.GetWeather(canceledContext(), lat, lon, ""); err == nil {
+			t.Error("GetWeather with canceled context: expected error")
+		}
+		if _, err := client.GetForecast(canceledContext(), lat, lon, ""); err == nil {
+			t.Error("GetForecast with canceled context: expected error")
+		}
+	})
+}
+
+// TestPlacesClient проверяет реализацию PlacesClient: в радиусе от точки
+// должно находиться хотя бы одно место с доступными деталями
+func TestPlacesClient(t *testing.T, client service.PlacesClient, lat, lon, radius float64) {
+	t.Helper()
+
+	places, err := client.GetPlaces(context.Background(), lat, lon, radius, "")
+	if err != nil {
+		t.Fatalf("GetPlaces: %v", err)
+	}
+	if len(places) == 0 {
+		t.Fatal("GetPlaces returned no places")
+	}
+
+	t.Run("GetPlaces", func(t *testing.T) {
+		for i, place := range places {
+			if place.Xid == "" {
+				t.Errorf("place %d has empty xid", i)
+			}
+			if place.Name == "" {
+				t.Errorf("place %d (%s) has empty name", i, place.Xid)
+			}
+			if place.Distance < 0 {
+				t.Errorf("place %d (%s) has negative distance %v", i, place.Xid, place.Distance)
+			}
+			checkCoordinates(t, "place", i, place.Lat, place.Lon)
+			checkUnits(t, place.Units)
+			checkCategories(t, place)
+		}
+	})
+
+	t.Run("GetPlacesInArea", func(t *testing.T) {
+		// Квадрат, описанный вокруг круга поиска
+		dLat := radius / 111320
+		dLon := dLat / math.Cos(lat*math.Pi/180)
+		bbox := model.BBox{West: lon - dLon, South: lat - dLat, East: lon + dLon, North: lat + dLat}
+
+		inArea, err := client.GetPlacesInArea(context.Background(), model.Area{BBox: &bbox}, "")
+		if err != nil {
+			t.Fatalf("GetPlacesInArea: %v", err)
+		}
+		for i, place := range inArea {
+			if !bbox.Contains(place.Lat, place.Lon) {
+				t.Errorf("place %d (%s) at %v,%v is outside bbox %+v", i, place.Xid, place.Lat, place.Lon, bbox)
+			}
+			checkUnits(t, place.Units)
+			checkCategories(t, place)
+		}
+	})
+
+	t.Run("GetPlaceDetails", func(t *testing.T) {
+		details, err := client.GetPlaceDetails(context.Background(), places[0].Xid, "")
+		if err != nil {
+			t.Fatalf("GetPlaceDetails(%s): %v", places[0].Xid, err)
+		}
+		if details == nil {
+			t.Fatal("GetPlaceDetails returned nil place without error")
+		}
+		if details.Xid != places[0].Xid {
+			t.Errorf("GetPlaceDetails(%s) returned xid %s", places[0].Xid, details.Xid)
+		}
+		checkUnits(t, details.Units)
+		checkCategories(t, *details)
+	})
+
+	t.Run("GetPlaceDetailsUnknown", func(t *testing.T) {
+		if _, err := client.GetPlaceDetails(context.Background(), "servicetest-unknown-place", ""); err == nil {
+			t.Error("GetPlaceDetails for unknown xid: expected error")
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		if _, err := client.GetPlaces(canceledContext(), lat, lon, radius, ""); err == nil {
+			t.Error("GetPlaces with canceled context: expected error")
+		}
+		bbox := model.BBox{West: lon - 0.01, South: lat - 0.01, East: lon + 0.01, North: lat + 0.01}
+		if _, err := client.GetPlacesInArea(canceledContext(), model.Area{BBox: &bbox}, ""); err == nil {
+			t.Error("GetPlacesInArea with canceled context: expected error")
+		}
+		if _, err := client.GetPlaceDetails(canceledContext(), places[0].Xid, ""); err == nil {
+			t.Error("GetPlaceDetails with canceled context: expected error")
+		}
+	})
+}
+
+// TestSummaryClient проверяет реализацию SummaryClient.
+// article — тег wikipedia существующей статьи, например "ru:Новосибирск"
+func TestSummaryClient(t *testing.T, client service.SummaryClient, article string) {
+	t.Helper()
+
+	lang, _, _ := strings.Cut(article, ":")
+
+	t.Run("GetSummary", func(t *testing.T) {
+		// Пустой язык и язык тега дают одну и ту же статью
+		for _, requested := range []string{"", lang} {
+			summary, err := client.GetSummary(context.Background(), article, requested)
+			if err != nil {
+				t.Fatalf("GetSummary(%q, %q): %v", article, requested, err)
+			}
+			if summary == nil {
+				t.Fatal("GetSummary returned nil summary without error")
+			}
+			if summary.Extract == "" {
+				t.Errorf("GetSummary(%q, %q) returned empty extract", article, requested)
+			}
+		}
+	})
+
+	t.Run("GetSummaryUnknown", func(t *testing.T) {
+		unknown := lang + ":Servicetest unknown article"
+		if _, err := client.GetSummary(context.Background(), unknown, ""); !errors.Is(err, model.ErrNotFound) {
+			t.Errorf("GetSummary(%q) error = %v, want model.ErrNotFound", unknown, err)
+		}
+		// У несуществующей статьи нет и версий на других языках
+		other := "eo"
+		if lang == other {
+			other = "la"
+		}
+		if _, err := client.GetSummary(context.Background(), unknown, other); !errors.Is(err, model.ErrNotFound) {
+			t.Errorf("GetSummary(%q, %q) error = %v, want model.ErrNotFound", unknown, other, err)
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		if _, err := client.GetSummary(canceledContext(), article, ""); err == nil {
+			t.Error("GetSummary with canceled context: expected error")
+		}
+	})
+}
+
+// checkCategories — категории провайдера переводятся в таксономию сервиса
+func checkCategories(t *testing.T, place model.Place) {
+	t.Helper()
+	if place.PrimaryCategory == nil {
+		t.Errorf("place %s has no primary category", place.Xid)
+		return
+	}
+	if _, ok := model.CategoryByID(place.PrimaryCategory.ID); !ok {
+		t.Errorf("place %s has unknown primary category %q", place.Xid, place.PrimaryCategory.ID)
+	}
+	for _, category := range place.Categories {
+		if _, ok := model.CategoryByID(category.ID); !ok {
+			t.Errorf("place %s has unknown category %q", place.Xid, category.ID)
+		}
+	}
+}
+
+func checkCoordinates(t *testing.T, kind string, i int, lat, lon float64) {
+	t.Helper()
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		t.Errorf("%s %d has invalid coordinates %v,%v", kind, i, lat, lon)
+	}
+}
+
+// checkWeather — порты всегда отдают погоду в метрической системе,
+// пересчёт в другие системы делает сервис
+func checkWeather(t *testing.T, weather model.Weather) {
+	t.Helper()
+	checkUnits(t, weather.Units)
+	if weather.Humidity < 0 || weather.Humidity > 100 {
+		t.Errorf("humidity %d is out of range", weather.Humidity)
+	}
+	if weather.WindSpeed < 0 {
+		t.Errorf("wind speed %v is negative", weather.WindSpeed)
+	}
+}
+
+func checkUnits(t *testing.T, units string) {
+	t.Helper()
+	if units != model.UnitsMetric {
+		t.Errorf("units = %q, want %q", units, model.UnitsMetric)
+	}
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
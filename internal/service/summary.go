@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"places/internal/model"
+)
+
+// placeDetails получает детали места. Если тег wikipedia известен из списка мест,
+// статья запрашивается параллельно с деталями, иначе — после них по тегу из деталей
+func (s *service) placeDetails(ctx context.Context, p model.Place) model.Place {
+	var summary *model.Summary
+	var wg sync.WaitGroup
+	if p.Wikipedia != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary = s.summary(ctx, p.Wikipedia)
+		}()
+	}
+
+	details, err := s.placesClient.GetPlaceDetails(ctx, p.Xid, LanguageFromContext(ctx))
+	wg.Wait()
+
+	if err == nil && details != nil {
+		if p.Wikipedia == "" {
+			summary = s.summary(ctx, details.Wikipedia)
+		}
+		// Детали главнее, а место из списка, возможно склеенное из дубликатов,
+		// дополняет их категориями, расстоянием, часами работы и ссылками
+		p = mergePlaces(*details, p)
+	}
+	return withSummary(p, summary)
+}
+
+// summary возвращает статью по тегу wikipedia или nil: без статьи место
+// показывается с описанием провайдера
+func (s *service) summary(ctx context.Context, article string) *model.Summary {
+	if s.summaryClient == nil || article == "" {
+		return nil
+	}
+	summary, err := s.summaryClient.GetSummary(ctx, article, LanguageFromContext(ctx))
+	if err != nil {
+		return nil
+	}
+	return summary
+}
+
+// withSummary ставит начало статьи перед описанием провайдера, в котором
+// остаются адрес, контакты и часы работы
+func withSummary(p model.Place, summary *model.Summary) model.Place {
+	if summary == nil || summary.Extract == "" {
+		return p
+	}
+	if p.Description == "" {
+		p.Description = summary.Extract
+	} else {
+		p.Description = summary.Extract + "\n\n" + p.Description
+	}
+	if p.Image == "" {
+		p.Image = summary.Image
+	}
+	return p
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// placeDetailsFunc отдаёт детали из карты по xid. Пока открыт release,
+// ответ задерживается, чтобы проверить параллельность запроса статьи
+type placeDetailsFunc struct {
+	PlacesClient
+	details map[string]model.Place
+	release chan struct{}
+}
+
+func (p *placeDetailsFunc) GetPlaceDetails(_ context.Context, xid, _ string) (*model.Place, error) {
+	if p.release != nil {
+		<-p.release
+	}
+	details, ok := p.details[xid]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &details, nil
+}
+
+// summaryFunc отдаёт статьи по тегу и запоминает запрошенные теги
+type summaryFunc struct {
+	mu        sync.Mutex
+	summaries map[string]model.Summary
+	articles  []string
+	// requested закрывается при первом запросе
+	requested chan struct{}
+}
+
+func (s *summaryFunc) GetSummary(_ context.Context, article, _ string) (*model.Summary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.articles) == 0 && s.requested != nil {
+		close(s.requested)
+	}
+	s.articles = append(s.articles, article)
+	summary, ok := s.summaries[article]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &summary, nil
+}
+
+func TestPlaceDetailsSummary(t *testing.T) {
+	places := &placeDetailsFunc{details: map[string]model.Place{
+		"museum":  {Xid: "museum", Name: "Музей", Description: "Address: Пирогова, 2", Wikipedia: "ru:Музей"},
+		"theatre": {Xid: "theatre", Name: "Театр", Image: "https://img.example/osm.jpg", Wikipedia: "ru:Театр"},
+		"cafe":    {Xid: "cafe", Name: "Кафе", Description: "Phone: +7 000"},
+	}}
+	summaries := &summaryFunc{summaries: map[string]model.Summary{
+		"ru:Музей": {Extract: "Музей археологии.", Image: "https://img.example/museum.jpg"},
+		"ru:Театр": {Extract: "Театр оперы и балета.", Image: "https://img.example/theatre.jpg"},
+	}}
+	srv := NewService(nil, nil, places, summaries).(*service)
+
+	got := srv.enrichPlacesWithDetails(context.Background(), []model.Place{
+		{Xid: "museum", Distance: 310, Wikipedia: "ru:Музей"},
+		// Тег известен только из деталей
+		{Xid: "theatre", Distance: 450},
+		{Xid: "cafe", Distance: 540},
+		{Xid: "unknown", Name: "Без деталей", Wikipedia: "ru:Нет статьи"},
+	})
+
+	want := []model.Place{
+		{Xid: "museum", Name: "Музей", Distance: 310, Wikipedia: "ru:Музей",
+			Description: "Музей археологии.\n\nAddress: Пирогова, 2", Image: "https://img.example/museum.jpg"},
+		{Xid: "theatre", Name: "Театр", Distance: 450, Wikipedia: "ru:Театр",
+			Description: "Театр оперы и балета.", Image: "https://img.example/osm.jpg"},
+		{Xid: "cafe", Name: "Кафе", Distance: 540, Description: "Phone: +7 000"},
+		{Xid: "unknown", Name: "Без деталей", Wikipedia: "ru:Нет статьи"},
+	}
+	for i := range want {
+		if got[i].Description != want[i].Description || got[i].Image != want[i].Image || got[i].Distance != want[i].Distance {
+			t.Errorf("place %s = %+v, want %+v", want[i].Xid, got[i], want[i])
+		}
+	}
+	if len(summaries.articles) != 3 {
+		t.Errorf("articles = %v, want one request per tag", summaries.articles)
+	}
+}
+
+func TestPlaceDetailsKeepMergedFields(t *testing.T) {
+	places := &placeDetailsFunc{details: map[string]model.Place{
+		"museum-node": {Xid: "museum-node", Name: "Музей", Kinds: "entertainment.museum",
+			Categories: []model.CategoryRef{{ID: "sights.museum"}}, Description: "Address: Пирогова, 2"},
+	}}
+	srv := NewService(nil, nil, places, nil).(*service)
+
+	// Точка и контур одного музея: детали запрашиваются по одной из них
+	deduped := dedupPlaces([]model.Place{
+		{Xid: "museum-node", Name: "Музей", Lat: 54.84, Lon: 83.09, Distance: 310, Kinds: "entertainment.museum",
+			Categories: []model.CategoryRef{{ID: "sights.museum"}}},
+		{Xid: "museum-way", Name: "Музей", Lat: 54.8401, Lon: 83.09, Distance: 320, Kinds: "building.historic",
+			Categories: []model.CategoryRef{{ID: "sights.historic"}}, OpeningHours: "Tu-Su 10:00-18:00",
+			WebSite: "https://museum.example", Wikipedia: "ru:Музей", Image: "https://img.example/way.jpg"},
+	})
+	if len(deduped) != 1 {
+		t.Fatalf("deduped = %+v, want one place", deduped)
+	}
+
+	got := srv.enrichPlacesWithDetails(context.Background(), deduped)[0]
+	if got.Description != "Address: Пирогова, 2" || got.Distance != 310 {
+		t.Errorf("details are lost: %+v", got)
+	}
+	if got.Kinds != "entertainment.museum, building.historic" || !hasCategoryID(got.Categories, "sights.historic") {
+		t.Errorf("merged categories are lost: kinds %q, categories %+v", got.Kinds, got.Categories)
+	}
+	if got.OpeningHours != "Tu-Su 10:00-18:00" || got.WebSite != "https://museum.example" ||
+		got.Wikipedia != "ru:Музей" || got.Image != "https://img.example/way.jpg" {
+		t.Errorf("merged fields are lost: %+v", got)
+	}
+}
+
+func TestPlaceDetailsSummaryInParallel(t *testing.T) {
+	places := &placeDetailsFunc{
+		details: map[string]model.Place{"museum": {Xid: "museum", Name: "Музей", Wikipedia: "ru:Музей"}},
+		release: make(chan struct{}),
+	}
+	summaries := &summaryFunc{
+		summaries: map[string]model.Summary{"ru:Музей": {Extract: "Музей археологии."}},
+		requested: make(chan struct{}),
+	}
+	srv := NewService(nil, nil, places, summaries).(*service)
+
+	// Детали не отвечают, пока не запрошена статья
+	go func() {
+		select {
+		case <-summaries.requested:
+		case <-time.After(time.Second):
+		}
+		close(places.release)
+	}()
+
+	start := time.Now()
+	p := srv.placeDetails(context.Background(), model.Place{Xid: "museum", Wikipedia: "ru:Музей"})
+	if time.Since(start) >= time.Second {
+		t.Error("summary was not requested while waiting for details")
+	}
+	if p.Description != "Музей археологии." {
+		t.Errorf("description = %q", p.Description)
+	}
+}
+
+func TestPlaceDetailsWithoutSummaryClient(t *testing.T) {
+	places := &placeDetailsFunc{details: map[string]model.Place{
+		"museum": {Xid: "museum", Name: "Музей", Description: "Address: Пирогова, 2", Wikipedia: "ru:Музей"},
+	}}
+	srv := NewService(nil, nil, places, nil)
+
+	p, err := srv.GetPlaceDetails(context.Background(), "museum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Description != "Address: Пирогова, 2" {
+		t.Errorf("description = %q", p.Description)
+	}
+}
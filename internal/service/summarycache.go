@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"places/internal/model"
+)
+
+// summaryCacheSize — наибольшее число кешированных статей
+const summaryCacheSize = 5000
+
+// cachedSummaries запоминает статьи и их отсутствие: детали локации запрашивают
+// статьи для всех мест вокруг, и соседние запросы просят те же статьи
+type cachedSummaries struct {
+	SummaryClient
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]summaryEntry
+}
+
+type summaryEntry struct {
+	// summary равна nil, если статьи нет
+	summary *model.Summary
+	expires time.Time
+}
+
+// CacheSummaries кеширует ответы client на ttl. Ошибки, кроме model.ErrNotFound, не кешируются
+func CacheSummaries(client SummaryClient, ttl time.Duration) SummaryClient {
+	return &cachedSummaries{
+		SummaryClient: client,
+		ttl:           ttl,
+		size:          summaryCacheSize,
+		now:           time.Now,
+		entries:       make(map[string]summaryEntry),
+	}
+}
+
+func (c *cachedSummaries) GetSummary(ctx context.Context, article, lang string) (*model.Summary, error) {
+	key := article + "\x00" + lang
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		if entry.summary == nil {
+			return nil, model.ErrNotFound
+		}
+		summary := *entry.summary
+		return &summary, nil
+	}
+
+	summary, err := c.SummaryClient.GetSummary(ctx, article, lang)
+	if (err != nil && !errors.Is(err, model.ErrNotFound)) || (err == nil && summary == nil) {
+		return summary, err
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	if len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	c.entries[key] = summaryEntry{summary: summary, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return summary, err
+}
+
+// evict удаляет устаревшие записи, а если их нет — запись, которая устареет первой
+func (c *cachedSummaries) evict(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.size {
+		delete(c.entries, oldestKey)
+	}
+}
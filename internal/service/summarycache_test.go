@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+// countingSummaries отдаёт статью "ru:Музей" и считает обращения; fail включает сбой
+type countingSummaries struct {
+	calls int
+	fail  bool
+}
+
+func (c *countingSummaries) GetSummary(_ context.Context, article, lang string) (*model.Summary, error) {
+	c.calls++
+	if c.fail {
+		return nil, errors.New("wikipedia is down")
+	}
+	if article != "ru:Музей" {
+		return nil, model.ErrNotFound
+	}
+	return &model.Summary{Extract: "Музей (" + lang + ")"}, nil
+}
+
+func TestCacheSummaries(t *testing.T) {
+	ctx := context.Background()
+	upstream := &countingSummaries{}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	client := CacheSummaries(upstream, time.Hour).(*cachedSummaries)
+	client.now = func() time.Time { return now }
+
+	for range 3 {
+		if s, err := client.GetSummary(ctx, "ru:Музей", "ru"); err != nil || s.Extract != "Музей (ru)" {
+			t.Fatalf("summary = %+v, err = %v", s, err)
+		}
+		if _, err := client.GetSummary(ctx, "ru:Нет", "ru"); !errors.Is(err, model.ErrNotFound) {
+			t.Fatalf("missing article: error = %v", err)
+		}
+	}
+	if upstream.calls != 2 {
+		t.Fatalf("calls = %d, want one per article", upstream.calls)
+	}
+
+	// Язык входит в ключ
+	if s, _ := client.GetSummary(ctx, "ru:Музей", "en"); s == nil || s.Extract != "Музей (en)" || upstream.calls != 3 {
+		t.Errorf("other language: summary = %+v, calls = %d", s, upstream.calls)
+	}
+
+	// Сбой не кешируется, устаревшая запись запрашивается заново
+	now = now.Add(2 * time.Hour)
+	upstream.fail = true
+	if _, err := client.GetSummary(ctx, "ru:Музей", "ru"); err == nil {
+		t.Fatal("expected upstream error")
+	}
+	upstream.fail = false
+	if s, err := client.GetSummary(ctx, "ru:Музей", "ru"); err != nil || s == nil || upstream.calls != 5 {
+		t.Errorf("after failure: summary = %+v, err = %v, calls = %d", s, err, upstream.calls)
+	}
+}
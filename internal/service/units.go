@@ -0,0 +1,139 @@
+package service
+
+import (
+	"math"
+	"places/internal/model"
+)
+
+/*
+	Провайдеры всегда запрашиваются в метрических единицах, а перевод в
+	систему клиента делается локально. Так один и тот же ответ провайдера
+	подходит клиентам с любыми единицами. Функции возвращают копии и не
+	изменяют исходные значения
+*/
+
+const (
+	feetPerMeter = 3.28084
+	mphPerMs     = 2.236936
+	kelvinOffset = 273.15
+)
+
+// ValidUnits сообщает, поддерживается ли система единиц
+func ValidUnits(units string) bool {
+	switch units {
+	case model.UnitsMetric, model.UnitsImperial, model.UnitsStandard:
+		return true
+	}
+	return false
+}
+
+func convertWeather(w *model.Weather, units string) *model.Weather {
+	if w == nil {
+		return nil
+	}
+	converted := *w
+	from := unitsOrMetric(w.Units)
+	if from == units {
+		converted.Units = units
+		return &converted
+	}
+
+	converted.Temp = fromCelsius(toCelsius(w.Temp, from), units)
+	converted.FeelsLike = fromCelsius(toCelsius(w.FeelsLike, from), units)
+	converted.WindSpeed = fromMetersPerSecond(toMetersPerSecond(w.WindSpeed, from), units)
+	converted.Units = units
+	return &converted
+}
+
+func convertForecast(f *model.Forecast, units string) *model.Forecast {
+	if f == nil {
+		return nil
+	}
+	converted := &model.Forecast{Items: make([]model.ForecastItem, len(f.Items))}
+	for i, item := range f.Items {
+		converted.Items[i] = model.ForecastItem{
+			Time:    item.Time,
+			Weather: *convertWeather(&item.Weather, units),
+		}
+	}
+	return converted
+}
+
+func convertPlace(p model.Place, units string) model.Place {
+	from := unitsOrMetric(p.Units)
+	if from != units {
+		p.Distance = fromMeters(toMeters(p.Distance, from), units)
+	}
+	p.Units = units
+	return p
+}
+
+func convertPlaces(places []model.Place, units string) []model.Place {
+	if places == nil {
+		return nil
+	}
+	converted := make([]model.Place, len(places))
+	for i, p := range places {
+		converted[i] = convertPlace(p, units)
+	}
+	return converted
+}
+
+func unitsOrMetric(units string) string {
+	if units == "" {
+		return model.UnitsMetric
+	}
+	return units
+}
+
+func toCelsius(t float64, units string) float64 {
+	switch units {
+	case model.UnitsImperial:
+		return (t - 32) * 5 / 9
+	case model.UnitsStandard:
+		return t - kelvinOffset
+	}
+	return t
+}
+
+func fromCelsius(t float64, units string) float64 {
+	switch units {
+	case model.UnitsImperial:
+		return round2(t*9/5 + 32)
+	case model.UnitsStandard:
+		return round2(t + kelvinOffset)
+	}
+	return round2(t)
+}
+
+func toMetersPerSecond(v float64, units string) float64 {
+	if units == model.UnitsImperial {
+		return v / mphPerMs
+	}
+	return v
+}
+
+func fromMetersPerSecond(v float64, units string) float64 {
+	if units == model.UnitsImperial {
+		return round2(v * mphPerMs)
+	}
+	return round2(v)
+}
+
+func toMeters(d float64, units string) float64 {
+	if units == model.UnitsImperial {
+		return d / feetPerMeter
+	}
+	return d
+}
+
+func fromMeters(d float64, units string) float64 {
+	if units == model.UnitsImperial {
+		return round2(d * feetPerMeter)
+	}
+	return round2(d)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"places/internal/model"
+)
+
+func TestConvertWeather(t *testing.T) {
+	metric := model.Weather{Temp: 20, FeelsLike: -5, WindSpeed: 10, Humidity: 60, Units: model.UnitsMetric}
+
+	tests := []struct {
+		name  string
+		in    model.Weather
+		units string
+		want  model.Weather
+	}{
+		{
+			name:  "metric to metric",
+			in:    metric,
+			units: model.UnitsMetric,
+			want:  model.Weather{Temp: 20, FeelsLike: -5, WindSpeed: 10, Humidity: 60, Units: model.UnitsMetric},
+		},
+		{
+			name:  "metric to imperial",
+			in:    metric,
+			units: model.UnitsImperial,
+			want:  model.Weather{Temp: 68, FeelsLike: 23, WindSpeed: 22.37, Humidity: 60, Units: model.UnitsImperial},
+		},
+		{
+			name:  "metric to standard",
+			in:    metric,
+			units: model.UnitsStandard,
+			want:  model.Weather{Temp: 293.15, FeelsLike: 268.15, WindSpeed: 10, Humidity: 60, Units: model.UnitsStandard},
+		},
+		{
+			name:  "imperial to standard",
+			in:    model.Weather{Temp: 68, FeelsLike: 23, WindSpeed: 22.37, Units: model.UnitsImperial},
+			units: model.UnitsStandard,
+			want:  model.Weather{Temp: 293.15, FeelsLike: 268.15, WindSpeed: 10, Units: model.UnitsStandard},
+		},
+		{
+			name:  "empty units treated as metric",
+			in:    model.Weather{Temp: 0, WindSpeed: 1},
+			units: model.UnitsImperial,
+			want:  model.Weather{Temp: 32, FeelsLike: 32, WindSpeed: 2.24, Units: model.UnitsImperial},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			got := convertWeather(&in, tt.units)
+			if *got != tt.want {
+				t.Errorf("convertWeather() = %+v, want %+v", *got, tt.want)
+			}
+			if in != tt.in {
+				t.Errorf("convertWeather modified its argument: %+v", in)
+			}
+		})
+	}
+
+	if convertWeather(nil, model.UnitsImperial) != nil {
+		t.Error("convertWeather(nil) != nil")
+	}
+}
+
+func TestConvertForecast(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	forecast := &model.Forecast{Items: []model.ForecastItem{
+		{Time: at, Weather: model.Weather{Temp: 10, WindSpeed: 5, Units: model.UnitsMetric}},
+	}}
+
+	got := convertForecast(forecast, model.UnitsStandard)
+	want := model.Weather{Temp: 283.15, FeelsLike: 273.15, WindSpeed: 5, Units: model.UnitsStandard}
+	if len(got.Items) != 1 || got.Items[0].Time != at || got.Items[0].Weather != want {
+		t.Errorf("convertForecast() = %+v", got.Items)
+	}
+	if forecast.Items[0].Units != model.UnitsMetric {
+		t.Error("convertForecast modified its argument")
+	}
+}
+
+func TestConvertPlace(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       model.Place
+		units    string
+		distance float64
+	}{
+		{"metric to metric", model.Place{Distance: 1000, Units: model.UnitsMetric}, model.UnitsMetric, 1000},
+		{"metric to imperial", model.Place{Distance: 1000, Units: model.UnitsMetric}, model.UnitsImperial, 3280.84},
+		// В стандартной системе расстояния, как и в метрической, в метрах
+		{"metric to standard", model.Place{Distance: 1000, Units: model.UnitsMetric}, model.UnitsStandard, 1000},
+		{"imperial to metric", model.Place{Distance: 3280.84, Units: model.UnitsImperial}, model.UnitsMetric, 1000},
+		{"empty units treated as metric", model.Place{Distance: 100}, model.UnitsImperial, 328.08},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertPlace(tt.in, tt.units)
+			if got.Distance != tt.distance || got.Units != tt.units {
+				t.Errorf("convertPlace() = distance %v units %q, want %v %q", got.Distance, got.Units, tt.distance, tt.units)
+			}
+		})
+	}
+
+	if convertPlaces(nil, model.UnitsImperial) != nil {
+		t.Error("convertPlaces(nil) != nil")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func LoadEnv(filename string) {
@@ -41,3 +43,47 @@ func LoadEnv(filename string) {
 		log.Fatal(err)
 	}
 }
+
+// GetEnv возвращает значение переменной окружения или def, если она не задана
+func GetEnv(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// GetEnvFloat возвращает числовое значение переменной окружения или def
+func GetEnvFloat(key string, def float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetEnvInt возвращает целое значение переменной окружения или def
+func GetEnvInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetEnvDuration возвращает длительность из переменной окружения ("24h", "90m") или def
+func GetEnvDuration(key string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetEnvBool возвращает логическое значение переменной окружения ("true", "false", "1", "0") или def
+func GetEnvBool(key string, def bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
@@ -1,4 +1,12 @@
 const API = '/api';
+const LANG = document.documentElement.lang || 'ru';
+// Случайная сессия поля ввода: сервер отменяет устаревшие подсказки этой сессии
+const SESSION = window.crypto?.randomUUID?.() ?? Math.random().toString(36).slice(2) + Date.now().toString(36);
+// SUGGEST_DELAY — пауза в наборе, после которой запрашиваются подсказки, мс
+const SUGGEST_DELAY = 200;
+
+let suggestTimer;
+let suggestController;
 
 document.addEventListener('DOMContentLoaded', init);
 
@@ -6,11 +14,45 @@ function init() {
     document.getElementById('searchInput').addEventListener('keypress', e => {
         if (e.key === 'Enter') search();
     });
+    document.getElementById('searchInput').addEventListener('input', () => {
+        clearTimeout(suggestTimer);
+        suggestTimer = setTimeout(suggest, SUGGEST_DELAY);
+    });
     document.getElementById('searchButton').addEventListener('click', search);
     document.getElementById('backButton').addEventListener('click', goBack);
 }
 
+// suggest показывает подсказки по мере ввода; ответ на устаревший запрос не выводится
+async function suggest() {
+    const query = document.getElementById('searchInput').value.trim();
+    // Короче трёх символов сервер подсказок не ищет
+    if (query.length < 3) return;
+
+    suggestController?.abort();
+    suggestController = new AbortController();
+
+    try {
+        const params = new URLSearchParams({q: query, session: SESSION, lang: LANG});
+        const res = await fetch(`${API}/autocomplete?${params}`, {signal: suggestController.signal});
+        // 409 — запрос вытеснен более новым запросом этой же сессии
+        if (!res.ok) return;
+
+        const locations = await res.json();
+        if (locations.length && document.getElementById('searchInput').value.trim() === query) {
+            showLocations(locations);
+        }
+    } catch (err) {
+        if (err.name !== 'AbortError') console.warn('autocomplete:', err);
+    }
+}
+
+function cancelSuggest() {
+    clearTimeout(suggestTimer);
+    suggestController?.abort();
+}
+
 async function search() {
+    cancelSuggest();
     const query = document.getElementById('searchInput').value.trim();
     if (!query) {
         showError('Введите название места');
@@ -25,7 +67,7 @@ async function search() {
         const res = await fetch(`${API}/search`, {
             method: 'POST',
             headers: {'Content-Type': 'application/json'},
-            body: JSON.stringify({query})
+            body: JSON.stringify({query, lang: LANG})
         });
 
         const locations = await res.json();
@@ -55,13 +97,14 @@ function showLocations(locations) {
 }
 
 async function selectLocation(location) {
+    cancelSuggest();
     show('loadingSection');
 
     try {
         const res = await fetch(`${API}/location/details`, {
             method: 'POST',
             headers: {'Content-Type': 'application/json'},
-            body: JSON.stringify({location})
+            body: JSON.stringify({location, lang: LANG})
         });
 
         const data = await res.json();